@@ -22,18 +22,59 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Configure PII redaction of tool output
+	redactor, err := types.LoadRedactor()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if redactor != nil {
+		types.GlobalOutputFilters = append(types.GlobalOutputFilters, redactor)
+	}
+
+	// Load access policy enforced by all tools
+	types.GlobalPolicy, err = types.LoadPolicy()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	// Track in-flight tool calls so client cancellation aborts Google requests
+	cancellations := types.NewCancellations()
+
 	s := server.NewMCPServer(
 		"Google Workspace MCP Server",
 		"0.1.0",
 		server.WithToolCapabilities(false),
+		server.WithHooks(cancellations.Hooks()),
+		server.WithToolHandlerMiddleware(types.FilterMiddleware()),
+		server.WithToolHandlerMiddleware(cancellations.Middleware()),
 	)
+	s.AddNotificationHandler("notifications/cancelled", cancellations.HandleCancelled)
 
 	// Register Docs tools
 	docsTools := tools.NewDocsTools(clients.ForDocs())
 	s.AddTool(docsTools.SearchTool(), mcp.NewTypedToolHandler(docsTools.SearchHandler))
 	s.AddTool(docsTools.GetContentTool(), mcp.NewTypedToolHandler(docsTools.GetContentHandler))
+	s.AddTool(docsTools.GetOutlineTool(), mcp.NewTypedToolHandler(docsTools.GetOutlineHandler))
+	s.AddTool(docsTools.ExportTool(), mcp.NewTypedToolHandler(docsTools.ExportHandler))
+	s.AddTool(docsTools.ListRevisionsTool(), mcp.NewTypedToolHandler(docsTools.ListRevisionsHandler))
+	s.AddTool(docsTools.DiffTool(), mcp.NewTypedToolHandler(docsTools.DiffHandler))
 	s.AddTool(docsTools.GetCommentsTool(), mcp.NewTypedToolHandler(docsTools.GetCommentsHandler))
 	s.AddTool(docsTools.ListInFolderTool(), mcp.NewTypedToolHandler(docsTools.ListInFolderHandler))
+	if types.GlobalWriteMode {
+		s.AddTool(docsTools.AddCommentTool(), mcp.NewTypedToolHandler(docsTools.AddCommentHandler))
+		s.AddTool(docsTools.ReplyToCommentTool(), mcp.NewTypedToolHandler(docsTools.ReplyToCommentHandler))
+		s.AddTool(docsTools.ResolveCommentTool(), mcp.NewTypedToolHandler(docsTools.ResolveCommentHandler))
+		s.AddTool(docsTools.CreateFromMarkdownTool(), mcp.NewTypedToolHandler(docsTools.CreateFromMarkdownHandler))
+	}
+
+	// Register Drive tools
+	driveTools := tools.NewDriveTools(clients.ForDrive())
+	s.AddTool(driveTools.SearchTool(), mcp.NewTypedToolHandler(driveTools.SearchHandler))
+	s.AddTool(driveTools.ListFolderTool(), mcp.NewTypedToolHandler(driveTools.ListFolderHandler))
+	s.AddTool(driveTools.GetMetadataTool(), mcp.NewTypedToolHandler(driveTools.GetMetadataHandler))
+	s.AddTool(driveTools.GetContentTool(), mcp.NewTypedToolHandler(driveTools.GetContentHandler))
 
 	// Register Calendar tools
 	calendarTools := tools.NewCalendarTools(clients.ForCalendar())
@@ -0,0 +1,269 @@
+package tools
+
+import (
+	"html"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/docs/v1"
+	"google.golang.org/api/drive/v3"
+)
+
+// Comment anchors in Docs are opaque IDs that the APIs can't resolve, so comments
+// are located by searching the document for the first line of their quoted text.
+
+// commentInlineStyles are the accepted values of the docs_get_content comments argument.
+var commentInlineStyles = map[string]bool{"": true, "footnotes": true, "blockquotes": true}
+
+// maxQuotePreview caps the quoted text shown with inlined comments.
+const maxQuotePreview = 80
+
+// DocsCommentLocation is where a comment's quoted text was found in the document.
+type DocsCommentLocation struct {
+	TabID    string `json:"tab_id,omitempty"`
+	TabTitle string `json:"tab_title"`
+	Heading  string `json:"heading,omitempty"` // Nearest heading above the quoted text
+	Link     string `json:"link"`
+}
+
+// normalizeSpace collapses runs of whitespace to single spaces and trims the ends.
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// quotedText returns the text a comment quotes, unescaping HTML quotes.
+func quotedText(c *drive.Comment) string {
+	if c.QuotedFileContent == nil {
+		return ""
+	}
+	if c.QuotedFileContent.MimeType == "text/html" {
+		return html.UnescapeString(c.QuotedFileContent.Value)
+	}
+	return c.QuotedFileContent.Value
+}
+
+// quoteKey returns the normalized first non-empty line of a comment's quoted text,
+// which is what is searched for in the document. Quotes can span paragraphs, but
+// their first line is always within one.
+func quoteKey(c *drive.Comment) string {
+	for _, line := range strings.Split(quotedText(c), "\n") {
+		if key := normalizeSpace(line); key != "" {
+			return key
+		}
+	}
+	return ""
+}
+
+// textLocation is a paragraph of a document and the section it is in.
+type textLocation struct {
+	tabID, tabTitle    string
+	heading, headingID string
+	text               string // Normalized paragraph text
+}
+
+// appendLocations appends a location for every paragraph in elements, including
+// those in tables. cur holds the tab and current heading, and is updated as
+// top-level headings are passed.
+func appendLocations(locs []textLocation, elements []*docs.StructuralElement, cur *textLocation, topLevel bool) []textLocation {
+	for _, elem := range elements {
+		switch {
+		case elem.Paragraph != nil:
+			text := normalizeSpace(paragraphText(elem.Paragraph))
+			if topLevel && paragraphHeadingLevel(elem.Paragraph) > 0 && text != "" {
+				cur.heading = text
+				cur.headingID = ""
+				if elem.Paragraph.ParagraphStyle != nil {
+					cur.headingID = elem.Paragraph.ParagraphStyle.HeadingId
+				}
+			}
+			if text != "" {
+				loc := *cur
+				loc.text = text
+				locs = append(locs, loc)
+			}
+		case elem.Table != nil:
+			for _, row := range elem.Table.TableRows {
+				for _, cell := range row.TableCells {
+					locs = appendLocations(locs, cell.Content, cur, false)
+				}
+			}
+		}
+	}
+	return locs
+}
+
+// locateQuote returns the first location containing key.
+func locateQuote(locs []textLocation, key string) (textLocation, bool) {
+	for _, loc := range locs {
+		if strings.Contains(loc.text, key) {
+			return loc, true
+		}
+	}
+	return textLocation{}, false
+}
+
+// commentAnchor is an open comment to place next to the text it quotes.
+type commentAnchor struct {
+	comment DocsComment
+	quote   string // Normalized first line of the quoted text
+	label   string // Footnote label, assigned when placed
+}
+
+// commentAnchors holds the comments to inline into converted Markdown. It is shared
+// by all tabs of a document, so each comment is placed once, at its first match.
+type commentAnchors struct {
+	style   string // "footnotes" or "blockquotes"
+	pending []*commentAnchor
+	placed  int
+}
+
+// newCommentAnchors creates commentAnchors for the given comments, in the given style.
+func newCommentAnchors(style string, comments []*drive.Comment) *commentAnchors {
+	ca := &commentAnchors{style: style}
+	for _, c := range comments {
+		ca.pending = append(ca.pending, &commentAnchor{
+			comment: convertComment(c),
+			quote:   quoteKey(c),
+		})
+	}
+	return ca
+}
+
+// match returns the unplaced comments whose quote is in text, marking them placed.
+func (ca *commentAnchors) match(text string) []*commentAnchor {
+	text = normalizeSpace(text)
+	if text == "" {
+		return nil
+	}
+	var matched []*commentAnchor
+	remaining := ca.pending[:0]
+	for _, a := range ca.pending {
+		if a.quote != "" && strings.Contains(text, a.quote) {
+			ca.placed++
+			a.label = "c" + strconv.Itoa(ca.placed)
+			matched = append(matched, a)
+		} else {
+			remaining = append(remaining, a)
+		}
+	}
+	ca.pending = remaining
+	return matched
+}
+
+// unplaced returns the comments that were not matched to any text.
+func (ca *commentAnchors) unplaced() []DocsComment {
+	var comments []DocsComment
+	for _, a := range ca.pending {
+		comments = append(comments, a.comment)
+	}
+	return comments
+}
+
+// commentMarkdown renders a comment and its replies as Markdown paragraphs.
+func commentMarkdown(a *commentAnchor, withQuote bool) string {
+	var sb strings.Builder
+	c := a.comment
+	sb.WriteString("**")
+	sb.WriteString(commentAuthor(c.Author, c.AuthorIsMe))
+	sb.WriteString("**")
+	if withQuote && c.QuotedText != "" {
+		quote := normalizeSpace(html.UnescapeString(c.QuotedText))
+		if r := []rune(quote); len(r) > maxQuotePreview {
+			quote = string(r[:maxQuotePreview]) + "…"
+		}
+		sb.WriteString(" on “")
+		sb.WriteString(escapeMarkdown(quote))
+		sb.WriteString("”")
+	}
+	sb.WriteString(": ")
+	sb.WriteString(escapeMarkdown(strings.TrimSpace(c.Content)))
+	for _, r := range c.Replies {
+		sb.WriteString("\n\n↳ **")
+		sb.WriteString(commentAuthor(r.Author, r.AuthorIsMe))
+		sb.WriteString("**: ")
+		sb.WriteString(escapeMarkdown(strings.TrimSpace(r.Content)))
+	}
+	return sb.String()
+}
+
+// commentAuthor returns the display name of a comment author.
+func commentAuthor(author string, isMe bool) string {
+	if isMe {
+		return "Me"
+	}
+	if author == "" {
+		return "Unknown"
+	}
+	return author
+}
+
+// placeComments attaches comments quoting para to the output. Footnote references
+// are appended to content, which is returned; blockquotes are queued until the
+// enclosing block ends (see flushComments). Code blocks can't hold references, so
+// comments on code are always queued as blockquotes.
+func (dc *docContext) placeComments(para *docs.Paragraph, content string, inCode bool) string {
+	if dc.opts.Comments == nil {
+		return content
+	}
+	matched := dc.opts.Comments.match(paragraphText(para))
+	if len(matched) == 0 {
+		return content
+	}
+	if dc.opts.Comments.style == "footnotes" && !inCode {
+		var refs strings.Builder
+		for _, a := range matched {
+			refs.WriteString("[^")
+			refs.WriteString(a.label)
+			refs.WriteString("]")
+		}
+		dc.commentNotes = append(dc.commentNotes, matched...)
+		return strings.TrimRight(content, " \t\n") + refs.String() + "\n"
+	}
+	dc.pendingComments = append(dc.pendingComments, matched...)
+	return content
+}
+
+// flushComments writes queued comments as blockquotes, indented to stay within
+// the last list item. Comments in table cells wait until the table is written.
+func (dc *docContext) flushComments(sb *strings.Builder) {
+	if len(dc.pendingComments) == 0 || dc.tableDepth > 0 {
+		return
+	}
+	if !strings.HasSuffix(sb.String(), "\n\n") {
+		sb.WriteString("\n")
+	}
+	for i, a := range dc.pendingComments {
+		if i > 0 {
+			sb.WriteString(dc.commentIndent)
+			sb.WriteString(">\n")
+		}
+		for _, line := range strings.Split(commentMarkdown(a, true), "\n") {
+			sb.WriteString(dc.commentIndent)
+			sb.WriteString(">")
+			if line != "" {
+				sb.WriteString(" ")
+				sb.WriteString(line)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	sb.WriteString("\n")
+	dc.pendingComments = nil
+}
+
+// writeCommentNotes appends footnote definitions for comments placed as footnotes.
+func writeCommentNotes(sb *strings.Builder, dc *docContext) {
+	if len(dc.commentNotes) == 0 {
+		return
+	}
+	if !strings.HasSuffix(sb.String(), "\n\n") {
+		sb.WriteString("\n")
+	}
+	for _, a := range dc.commentNotes {
+		sb.WriteString("[^")
+		sb.WriteString(a.label)
+		sb.WriteString("]: ")
+		sb.WriteString(indentContinuation(commentMarkdown(a, false), "    "))
+		sb.WriteString("\n")
+	}
+}
@@ -25,6 +25,8 @@ type CalendarGetEventsRequest struct {
 	IncludeAttachments bool   `json:"include_attachments"` // Include file attachments in response
 	PageToken          string `json:"page_token"`          // Continue from previous page
 	OrderBy            string `json:"order_by"`            // Sort order: startTime (default) or updated
+	FetchAll           bool   `json:"fetch_all"`           // Follow page tokens and merge all pages
+	MaxItems           int    `json:"max_items"`           // Cap on total events when FetchAll is set
 }
 
 // CalendarTools provides Google Calendar API tools.
@@ -77,6 +79,10 @@ func (c *CalendarTools) ListCalendarsHandler(ctx context.Context, request mcp.Ca
 	}
 
 	for _, cal := range calendarList.Items {
+		// Hide calendars denied by the access policy
+		if types.GlobalPolicy.Calendar.Check(cal.Id) != nil {
+			continue
+		}
 		response.Calendars = append(response.Calendars, CalendarInfo{
 			ID:         cal.Id,
 			Summary:    cal.Summary,
@@ -141,13 +147,15 @@ Returns a JSON object with an array of events, each containing:
 		mcp.WithString("order_by",
 			mcp.Description("Sort order: startTime (default) or updated"),
 		),
+		withFetchAll(),
 	)
 }
 
 // CalendarGetEventsResponse contains the list of events.
 type CalendarGetEventsResponse struct {
 	Events        []CalendarEventInfo `json:"events"`
-	NextPageToken string      `json:"next_page_token,omitempty"`
+	NextPageToken string              `json:"next_page_token,omitempty"`
+	Truncated     bool                `json:"truncated,omitempty"` // fetch_all stopped at max_items
 }
 
 // CalendarGetEventResponse contains a single event.
@@ -157,15 +165,16 @@ type CalendarGetEventResponse struct {
 
 // CalendarEventInfo represents a single event's information.
 type CalendarEventInfo struct {
-	ID          string           `json:"id"`
-	Summary     string           `json:"summary"`
-	Start       string           `json:"start"`
-	End         string           `json:"end"`
-	Location    string           `json:"location,omitempty"`
-	Description string           `json:"description,omitempty"`
-	HTMLLink    string           `json:"htmlLink"`
+	ID          string                   `json:"id"`
+	Summary     string                   `json:"summary"`
+	Start       string                   `json:"start"`
+	End         string                   `json:"end"`
+	Location    string                   `json:"location,omitempty"`
+	Description string                   `json:"description,omitempty"`
+	HTMLLink    string                   `json:"htmlLink"`
 	Attendees   []CalendarAttendeeInfo   `json:"attendees,omitempty"`
 	Attachments []CalendarAttachmentInfo `json:"attachments,omitempty"`
+	Warnings    []string                 `json:"warnings,omitempty"` // Untrusted content findings (MCP_HARDEN_UNTRUSTED)
 }
 
 // CalendarAttendeeInfo represents an event attendee.
@@ -191,6 +200,14 @@ func (c *CalendarTools) GetEventsHandler(ctx context.Context, request mcp.CallTo
 		calendarID = "primary"
 	}
 
+	resolvedID, err := c.resolveCalendarID(ctx, calendarID)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := types.GlobalPolicy.Calendar.Check(resolvedID); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	// Single event lookup
 	if args.EventID != "" {
 		event, err := c.calendarService.Events.Get(calendarID, args.EventID).Context(ctx).Do()
@@ -209,35 +226,6 @@ func (c *CalendarTools) GetEventsHandler(ctx context.Context, request mcp.CallTo
 		return mcp.NewToolResultText(data), nil
 	}
 
-	// List events with optional filters
-	listCall := c.calendarService.Events.List(calendarID).
-		Context(ctx).
-		SingleEvents(true)
-
-	// Set sort order
-	if args.OrderBy != "" {
-		listCall = listCall.OrderBy(args.OrderBy)
-	} else {
-		listCall = listCall.OrderBy("startTime")
-	}
-
-	// Apply pagination
-	if args.PageToken != "" {
-		listCall = listCall.PageToken(args.PageToken)
-	}
-
-	// Set time range
-	if args.TimeMin != "" {
-		listCall = listCall.TimeMin(args.TimeMin)
-	} else {
-		// Default to now
-		listCall = listCall.TimeMin(time.Now().Format(time.RFC3339))
-	}
-
-	if args.TimeMax != "" {
-		listCall = listCall.TimeMax(args.TimeMax)
-	}
-
 	// Set max results
 	maxResults := args.MaxResults
 	if maxResults <= 0 {
@@ -246,25 +234,78 @@ func (c *CalendarTools) GetEventsHandler(ctx context.Context, request mcp.CallTo
 	if maxResults > 2500 {
 		maxResults = 2500
 	}
-	listCall = listCall.MaxResults(int64(maxResults))
 
-	// Set search query
-	if args.Query != "" {
-		listCall = listCall.Q(args.Query)
-	}
+	// Resolve "now" once so every page uses the same time range
+	timeMin := time.Now().Format(time.RFC3339)
 
-	events, err := listCall.Do()
-	if err != nil {
-		return mcp.NewToolResultError("failed to list events: " + err.Error()), nil
-	}
+	fetch := func(ctx context.Context, pageToken string, pageSize int) ([]CalendarEventInfo, string, error) {
+		// List events with optional filters
+		listCall := c.calendarService.Events.List(calendarID).
+			Context(ctx).
+			SingleEvents(true).
+			MaxResults(int64(pageSize))
+
+		// Set sort order
+		if args.OrderBy != "" {
+			listCall = listCall.OrderBy(args.OrderBy)
+		} else {
+			listCall = listCall.OrderBy("startTime")
+		}
+
+		// Apply pagination
+		if pageToken != "" {
+			listCall = listCall.PageToken(pageToken)
+		}
+
+		// Set time range
+		if args.TimeMin != "" {
+			listCall = listCall.TimeMin(args.TimeMin)
+		} else {
+			// Default to now
+			listCall = listCall.TimeMin(timeMin)
+		}
+
+		if args.TimeMax != "" {
+			listCall = listCall.TimeMax(args.TimeMax)
+		}
 
-	response := CalendarGetEventsResponse{
-		Events:        make([]CalendarEventInfo, 0, len(events.Items)),
-		NextPageToken: events.NextPageToken,
+		// Set search query
+		if args.Query != "" {
+			listCall = listCall.Q(args.Query)
+		}
+
+		events, err := listCall.Do()
+		if err != nil {
+			return nil, "", err
+		}
+
+		infos := make([]CalendarEventInfo, 0, len(events.Items))
+		for _, event := range events.Items {
+			infos = append(infos, eventToInfo(event, args.IncludeAttachments))
+		}
+		return infos, events.NextPageToken, nil
 	}
 
-	for _, event := range events.Items {
-		response.Events = append(response.Events, eventToInfo(event, args.IncludeAttachments))
+	var response CalendarGetEventsResponse
+	if args.FetchAll {
+		paged, err := fetchAllPages(ctx, args.PageToken, maxResults, args.MaxItems, types.NewProgress(ctx, request), fetch)
+		if err != nil {
+			return mcp.NewToolResultError("failed to list events: " + err.Error()), nil
+		}
+		response = CalendarGetEventsResponse{
+			Events:        paged.Items,
+			NextPageToken: paged.NextPageToken,
+			Truncated:     paged.Truncated,
+		}
+	} else {
+		events, next, err := fetch(ctx, args.PageToken, maxResults)
+		if err != nil {
+			return mcp.NewToolResultError("failed to list events: " + err.Error()), nil
+		}
+		response = CalendarGetEventsResponse{
+			Events:        events,
+			NextPageToken: next,
+		}
 	}
 
 	data, err := types.MarshalResponse(response)
@@ -284,6 +325,14 @@ func eventToInfo(event *calendar.Event, includeAttachments bool) CalendarEventIn
 		HTMLLink:    event.HtmlLink,
 	}
 
+	// Sanitize attacker-controlled content when hardening is enabled
+	// (anyone can send an invite with an arbitrary title and description)
+	if types.GlobalHardenUntrusted {
+		info.Summary = sanitizeUntrustedField("summary", info.Summary, &info.Warnings)
+		info.Location = sanitizeUntrustedField("location", info.Location, &info.Warnings)
+		info.Description = wrapUntrusted("calendar:"+event.Id, info.Description, &info.Warnings)
+	}
+
 	// Handle start time (can be dateTime or date for all-day events)
 	if event.Start != nil {
 		if event.Start.DateTime != "" {
@@ -368,6 +417,9 @@ func (e CalendarGetEventsResponse) MarshalCompact() string {
 		writeEventCompact(&sb, event)
 	}
 
+	if e.Truncated {
+		sb.WriteString("\n\n(truncated at max_items)")
+	}
 	if e.NextPageToken != "" {
 		sb.WriteString("\n\nNext Page Token: ")
 		sb.WriteString(e.NextPageToken)
@@ -408,12 +460,24 @@ func writeEventCompact(sb *strings.Builder, event CalendarEventInfo) {
 	// Description
 	if event.Description != "" {
 		sb.WriteString("\n  Description: ")
-		// Truncate long descriptions and handle newlines
-		desc := strings.ReplaceAll(event.Description, "\n", " ")
-		if len(desc) > 200 {
-			desc = desc[:197] + "..."
+		if types.GlobalHardenUntrusted {
+			// Keep the untrusted block intact so its delimiters aren't truncated away
+			sb.WriteString("\n")
+			sb.WriteString(event.Description)
+		} else {
+			// Truncate long descriptions and handle newlines
+			desc := strings.ReplaceAll(event.Description, "\n", " ")
+			if len(desc) > 200 {
+				desc = desc[:197] + "..."
+			}
+			sb.WriteString(desc)
 		}
-		sb.WriteString(desc)
+	}
+
+	// Untrusted content warnings
+	for _, w := range event.Warnings {
+		sb.WriteString("\n  Warning: ")
+		sb.WriteString(w)
 	}
 
 	// Link
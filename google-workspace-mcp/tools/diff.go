@@ -0,0 +1,302 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Kinds of diff operations.
+const (
+	diffEqual  = ' '
+	diffDelete = '-'
+	diffInsert = '+'
+)
+
+// maxDiffEdits caps the edit distance myersDiff searches for. Its memory use grows
+// with the square of the distance, so texts that differ by more are diffed as one
+// block of removed lines followed by one block of added lines.
+const maxDiffEdits = 2000
+
+// markdownHeadingRe matches an ATX Markdown heading line.
+var markdownHeadingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*$`)
+
+// tabMarkerRe matches the tab separator lines written by snapshotMarkdown.
+var tabMarkerRe = regexp.MustCompile(`^<!-- tab: (.*) -->$`)
+
+// diffOp is a single line of a line diff.
+type diffOp struct {
+	kind byte   // diffEqual, diffDelete, or diffInsert
+	line string // Line text, without the trailing newline
+	a, b int    // 0-based line indexes in the old and new text before this line
+}
+
+// diffStats counts the changed lines of a diff.
+type diffStats struct {
+	added, removed int
+}
+
+// splitLines splits text into lines, ignoring a trailing newline.
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// diffLines computes a minimal line diff of a and b using Myers' algorithm.
+// Common leading and trailing lines are trimmed first, so memory use grows with
+// the size of the changes rather than the size of the texts.
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []diffOp
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, diffOp{kind: diffEqual, line: a[i], a: i, b: i})
+	}
+	for _, op := range myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		op.a += prefix
+		op.b += prefix
+		ops = append(ops, op)
+	}
+	for i := 0; i < suffix; i++ {
+		ai, bi := len(a)-suffix+i, len(b)-suffix+i
+		ops = append(ops, diffOp{kind: diffEqual, line: a[ai], a: ai, b: bi})
+	}
+	return ops
+}
+
+// myersDiff implements the greedy O((N+M)D) diff algorithm from Myers' "An O(ND)
+// Difference Algorithm and Its Variations", keeping one snapshot of the furthest
+// reaching paths per edit distance for backtracking. If the edit distance exceeds
+// maxDiffEdits, it returns replaceDiff(a, b) instead.
+func myersDiff(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+	offset := n + m
+	v := make([]int, 2*offset+2)
+	var trace [][]int // trace[d][k+d] is v[k] at the start of round d
+
+	var d int
+search:
+	for d = 0; d <= n+m; d++ {
+		if d > maxDiffEdits {
+			return replaceDiff(a, b)
+		}
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // Move down: insert from b
+			} else {
+				x = v[offset+k-1] + 1 // Move right: delete from a
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Backtrack from (n, m), collecting operations in reverse
+	var reversed []diffOp
+	x, y := n, m
+	for ; d > 0; d-- {
+		prev := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && prev[k-1+d] < prev[k+1+d]) {
+			prevK = k + 1
+		}
+		prevX := prev[prevK+d]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, diffOp{kind: diffEqual, line: a[x], a: x, b: y})
+		}
+		if x == prevX {
+			y--
+			reversed = append(reversed, diffOp{kind: diffInsert, line: b[y], a: x, b: y})
+		} else {
+			x--
+			reversed = append(reversed, diffOp{kind: diffDelete, line: a[x], a: x, b: y})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		reversed = append(reversed, diffOp{kind: diffEqual, line: a[x], a: x, b: y})
+	}
+
+	ops := make([]diffOp, len(reversed))
+	for i, op := range reversed {
+		ops[len(reversed)-1-i] = op
+	}
+	return ops
+}
+
+// replaceDiff returns a diff that removes all of a and then adds all of b.
+func replaceDiff(a, b []string) []diffOp {
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for i, line := range a {
+		ops = append(ops, diffOp{kind: diffDelete, line: line, a: i, b: 0})
+	}
+	for j, line := range b {
+		ops = append(ops, diffOp{kind: diffInsert, line: line, a: len(a), b: j})
+	}
+	return ops
+}
+
+// countChanges returns the number of added and removed lines in ops.
+func countChanges(ops []diffOp) diffStats {
+	var stats diffStats
+	for _, op := range ops {
+		switch op.kind {
+		case diffInsert:
+			stats.added++
+		case diffDelete:
+			stats.removed++
+		}
+	}
+	return stats
+}
+
+// unifiedDiff formats ops as a unified diff with the given number of context lines.
+// It returns an empty string if there are no changes.
+func unifiedDiff(ops []diffOp, fromName, toName string, context int) string {
+	var sb strings.Builder
+	for i := 0; i < len(ops); {
+		// Find the next change
+		for i < len(ops) && ops[i].kind == diffEqual {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+
+		// Extend the hunk while changes are close enough to share context
+		last := i
+		for j := i; j < len(ops) && j-last <= 2*context; j++ {
+			if ops[j].kind != diffEqual {
+				last = j
+			}
+		}
+		start := max(i-context, 0)
+		end := min(last+context+1, len(ops))
+
+		if sb.Len() == 0 {
+			fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		var aCount, bCount int
+		for _, op := range ops[start:end] {
+			if op.kind != diffInsert {
+				aCount++
+			}
+			if op.kind != diffDelete {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(ops[start].a, aCount), hunkRange(ops[start].b, bCount))
+		for _, op := range ops[start:end] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.line)
+			sb.WriteByte('\n')
+		}
+		i = end
+	}
+	return sb.String()
+}
+
+// hunkRange formats the line range of a unified diff hunk. start is the 0-based index
+// of the first line; empty ranges refer to the line before, as in GNU diff.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// sectionDiff formats the changed lines of ops grouped by the Markdown section
+// (tab and heading path) they fall in, omitting unchanged lines. Headings are
+// tracked across both versions, so removed lines are reported under the section
+// they used to be in. It returns an empty string if there are no changes.
+func sectionDiff(ops []diffOp) string {
+	var sb strings.Builder
+	var tab string
+	var headings []string // Heading text by level - 1
+	inFence := false
+	lastSection := ""
+
+	for _, op := range ops {
+		trimmed := strings.TrimSpace(op.line)
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			inFence = !inFence
+		case inFence:
+		case tabMarkerRe.MatchString(op.line):
+			tab = tabMarkerRe.FindStringSubmatch(op.line)[1]
+			headings = nil
+		case op.kind != diffDelete && markdownHeadingRe.MatchString(op.line):
+			// Only the new version's headings define the structure; deleted headings
+			// are reported as changes under their parent section
+			m := markdownHeadingRe.FindStringSubmatch(op.line)
+			level := len(m[1])
+			headings = append(headings[:min(level-1, len(headings))], make([]string, max(level-1-len(headings), 0))...)
+			headings = append(headings, m[2])
+		}
+		if op.kind == diffEqual {
+			continue
+		}
+
+		section := sectionPath(tab, headings)
+		if section != lastSection || sb.Len() == 0 {
+			if sb.Len() > 0 {
+				sb.WriteString("\n")
+			}
+			sb.WriteString("@@ ")
+			sb.WriteString(section)
+			sb.WriteString(" @@\n")
+			lastSection = section
+		}
+		sb.WriteByte(op.kind)
+		sb.WriteString(op.line)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// sectionPath formats a tab and heading path such as "Tab > Intro > Goals".
+func sectionPath(tab string, headings []string) string {
+	var parts []string
+	if tab != "" {
+		parts = append(parts, tab)
+	}
+	for _, h := range headings {
+		if h != "" {
+			parts = append(parts, h)
+		}
+	}
+	if len(parts) == 0 {
+		return "(start of document)"
+	}
+	return strings.Join(parts, " > ")
+}
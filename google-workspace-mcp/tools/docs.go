@@ -1,14 +1,31 @@
 package tools
 
 import (
+	"container/list"
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"google.golang.org/api/docs/v1"
 	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
 
 	"github.com/joelanford/mcp/google-workspace-mcp/types"
 )
@@ -18,56 +35,87 @@ var multipleNewlinesRe = regexp.MustCompile(`\n{3,}`)
 
 // DocsSearchRequest contains arguments for searching Google Docs via Drive API.
 type DocsSearchRequest struct {
-	Query          string `json:"query"`
-	PageSize       int    `json:"page_size"`
-	PageToken      string `json:"page_token"`       // Continue from previous page
-	OrderBy        string `json:"order_by"`         // Sort order: createdTime, modifiedTime, name, name_natural
-	ModifiedAfter  string `json:"modified_after"`   // RFC3339 date - only docs modified after this time
-	ModifiedBefore string `json:"modified_before"`  // RFC3339 date - only docs modified before this time
-	OwnerEmail     string `json:"owner_email"`      // Filter to docs owned by this email
+	Query          string            `json:"query"`
+	SearchMode     string            `json:"search_mode"` // name, fullText, or both
+	PageSize       int               `json:"page_size"`
+	PageToken      string            `json:"page_token"`      // Continue from previous page
+	OrderBy        string            `json:"order_by"`        // Sort order: createdTime, modifiedTime, name, name_natural
+	ModifiedAfter  string            `json:"modified_after"`  // RFC3339 date - only docs modified after this time
+	ModifiedBefore string            `json:"modified_before"` // RFC3339 date - only docs modified before this time
+	OwnerEmail     string            `json:"owner_email"`     // Filter to docs owned by this email
+	DriveQuery     *DriveQueryFilter `json:"drive_query"`     // Advanced structured filters
+	FetchAll       bool              `json:"fetch_all"`       // Follow page tokens and merge all pages
+	MaxItems       int               `json:"max_items"`       // Cap on total results when FetchAll is set
 }
 
 // DocsGetContentRequest contains arguments for getting document content.
 type DocsGetContentRequest struct {
-	DocumentID string `json:"document_id"`
+	DocumentID            string `json:"document_id"`
+	IncludeHeadersFooters bool   `json:"include_headers_footers"` // Render page headers and footers
+	IncludeImages         bool   `json:"include_images"`          // Return images as MCP image content
+	MaxImageBytes         int    `json:"max_image_bytes"`         // Per-image size cap for IncludeImages
+	InlineHTML            bool   `json:"inline_html"`             // Render styles Markdown lacks as inline HTML
+	HTMLTables            bool   `json:"html_tables"`             // Render tables Markdown can't represent as HTML
+	TabID                 string `json:"tab_id"`                  // Only return this tab
+	TabTitle              string `json:"tab_title"`               // Only return tabs with this title
+	Heading               string `json:"heading"`                 // Only return the section under this heading
+	HeadingOffset         int    `json:"heading_offset"`          // Shift heading levels down
+	Suggestions           string `json:"suggestions"`             // inline, accepted, or original
+	Comments              string `json:"comments"`                // Inline open comments as footnotes or blockquotes
 }
 
 // DocsListInFolderRequest contains arguments for listing docs in a folder.
 type DocsListInFolderRequest struct {
-	FolderID       string `json:"folder_id"`
-	PageSize       int    `json:"page_size"`
-	PageToken      string `json:"page_token"`       // Continue from previous page
-	OrderBy        string `json:"order_by"`         // Sort order: createdTime, modifiedTime, name, name_natural
-	ModifiedAfter  string `json:"modified_after"`   // RFC3339 date filter
-	ModifiedBefore string `json:"modified_before"`  // RFC3339 date filter
+	FolderID       string            `json:"folder_id"`
+	PageSize       int               `json:"page_size"`
+	PageToken      string            `json:"page_token"`      // Continue from previous page
+	OrderBy        string            `json:"order_by"`        // Sort order: createdTime, modifiedTime, name, name_natural
+	ModifiedAfter  string            `json:"modified_after"`  // RFC3339 date filter
+	ModifiedBefore string            `json:"modified_before"` // RFC3339 date filter
+	DriveQuery     *DriveQueryFilter `json:"drive_query"`     // Advanced structured filters
+	FetchAll       bool              `json:"fetch_all"`       // Follow page tokens and merge all pages
+	MaxItems       int               `json:"max_items"`       // Cap on total results when FetchAll is set
 }
 
 // DocsGetCommentsRequest contains arguments for getting document comments.
 type DocsGetCommentsRequest struct {
 	DocumentID      string `json:"document_id"`
 	IncludeResolved bool   `json:"include_resolved"`
-	PageToken       string `json:"page_token"`     // Continue from previous page
-	PageSize        int    `json:"page_size"`      // Max comments per page (default 100)
-	ModifiedAfter   string `json:"modified_after"` // RFC3339 date - only comments modified after this time
+	PageToken       string `json:"page_token"`        // Continue from previous page
+	PageSize        int    `json:"page_size"`         // Max comments per page (default 100)
+	ModifiedAfter   string `json:"modified_after"`    // RFC3339 date - only comments modified after this time
+	IncludeLocation bool   `json:"include_location"`  // Locate quoted text in the document
+	Author          string `json:"author"`            // Only comments by this email address or display name
+	Assignee        string `json:"assignee"`          // Only action items assigned to this email address, or "me"
+	MentionsMe      bool   `json:"mentions_me"`       // Only comments that mention the user
+	AwaitingMyReply bool   `json:"awaiting_my_reply"` // Only open threads the user is in where someone else spoke last
+	FetchAll        bool   `json:"fetch_all"`         // Follow page tokens and merge all pages
+	MaxItems        int    `json:"max_items"`         // Cap on total comments when FetchAll is set
 }
 
 // DocsSearchResult represents a single item in docs search results.
 type DocsSearchResult struct {
-	ID      string `json:"id"`
-	Title   string `json:"title,omitempty"`
-	Subject string `json:"subject,omitempty"`
-	Snippet string `json:"snippet,omitempty"`
+	ID           string   `json:"id"`
+	Title        string   `json:"title,omitempty"`
+	Subject      string   `json:"subject,omitempty"`
+	Snippet      string   `json:"snippet,omitempty"` // Text around the first match, with the match in **bold**
+	ModifiedTime string   `json:"modifiedTime,omitempty"`
+	Owners       []string `json:"owners,omitempty"` // Owner email addresses
+	WebViewLink  string   `json:"webViewLink,omitempty"`
+	Warnings     []string `json:"warnings,omitempty"` // Untrusted content findings (MCP_HARDEN_UNTRUSTED)
 }
 
 // DocsSearchResponse contains paginated search results.
 type DocsSearchResponse struct {
 	Results       []DocsSearchResult `json:"results"`
 	NextPageToken string             `json:"next_page_token,omitempty"`
+	Truncated     bool               `json:"truncated,omitempty"` // fetch_all stopped at max_items
 }
 
 // MarshalCompact returns a compact text representation of the search response.
-// Format: one result per line as "id | title" or "id | subject" (whichever is set)
-// with optional "Next Page Token: <token>" appended if pagination continues.
+// Format: one result per line as "id | title | modifiedTime" (title falls back to subject),
+// followed by an indented snippet line when available, with optional
+// "Next Page Token: <token>" appended if pagination continues.
 func (s DocsSearchResponse) MarshalCompact() string {
 	var sb strings.Builder
 	for _, r := range s.Results {
@@ -78,7 +126,24 @@ func (s DocsSearchResponse) MarshalCompact() string {
 		} else if r.Subject != "" {
 			sb.WriteString(r.Subject)
 		}
+		if r.ModifiedTime != "" {
+			sb.WriteString(" | ")
+			sb.WriteString(r.ModifiedTime)
+		}
 		sb.WriteString("\n")
+		if r.Snippet != "" {
+			sb.WriteString("    ")
+			sb.WriteString(r.Snippet)
+			sb.WriteString("\n")
+		}
+		for _, w := range r.Warnings {
+			sb.WriteString("    Warning: ")
+			sb.WriteString(w)
+			sb.WriteString("\n")
+		}
+	}
+	if s.Truncated {
+		sb.WriteString("\n(truncated at max_items)")
 	}
 	if s.NextPageToken != "" {
 		sb.WriteString("\nNext Page Token: ")
@@ -91,26 +156,57 @@ func (s DocsSearchResponse) MarshalCompact() string {
 type DocsTools struct {
 	docsService  *docs.Service
 	driveService *drive.Service
+	httpClient   *http.Client
+
+	// docsWriteService and driveWriteService have write scopes and are only used by
+	// write mode tools. They are nil unless write mode is enabled.
+	docsWriteService  *docs.Service
+	driveWriteService *drive.Service
+
+	// snapshots maps document IDs to the whole-document Markdown last returned,
+	// for docs_diff. snapshotOrder holds the same snapshots, most recently used first.
+	snapshotMu    sync.Mutex
+	snapshots     map[string]*list.Element
+	snapshotOrder *list.List
+	snapshotBytes int
+
+	// me caches the authenticated user's email address for comment filters.
+	meMu sync.Mutex
+	me   string
+
+	*filePolicy
 }
 
 // NewDocsTools creates a new DocsTools instance from the provided clients.
 func NewDocsTools(clients *types.DocsClients) *DocsTools {
 	return &DocsTools{
-		docsService:  clients.Docs,
-		driveService: clients.Drive,
+		docsService:       clients.Docs,
+		driveService:      clients.Drive,
+		httpClient:        clients.HTTP,
+		docsWriteService:  clients.DocsWrite,
+		driveWriteService: clients.DriveWrite,
+		snapshots:         map[string]*list.Element{},
+		snapshotOrder:     list.New(),
+		filePolicy:        newFilePolicy(clients.Drive),
 	}
 }
 
 // SearchTool returns the tool definition for searching Google Docs.
 func (d *DocsTools) SearchTool() mcp.Tool {
 	return mcp.NewTool("docs_search",
-		mcp.WithDescription(`Searches for Google Docs by name using Drive API (mimeType filter).
+		mcp.WithDescription(`Searches for Google Docs by name and/or content using Drive API (mimeType filter).
+
+Results include the modified time, owners, and link of each document. Content searches (search_mode fullText or both) also return a snippet of text around the first match for the top results.
 
 Returns:
     str: A formatted list of Google Docs matching the search query.`),
 		mcp.WithString("query",
 			mcp.Required(),
-			mcp.Description("Search string to find in document names"),
+			mcp.Description("Search string to find in document names or content"),
+		),
+		mcp.WithString("search_mode",
+			mcp.Description("Where to search: name (default), fullText (document content), or both. order_by is only supported with name"),
+			mcp.Enum("name", "fullText", "both"),
 		),
 		mcp.WithNumber("page_size",
 			mcp.Description("Maximum number of results to return (default 10)"),
@@ -132,6 +228,8 @@ Returns:
 		mcp.WithString("owner_email",
 			mcp.Description("Only include docs owned by this email address"),
 		),
+		withDriveQuery(false),
+		withFetchAll(),
 	)
 }
 
@@ -144,80 +242,230 @@ func (d *DocsTools) SearchHandler(ctx context.Context, request mcp.CallToolReque
 	pageSize := args.PageSize
 	if pageSize <= 0 {
 		pageSize = 10
+		// Use larger pages when following page tokens to save round trips
+		if args.FetchAll {
+			pageSize = 100
+		}
 	}
 
-	// Escape single quotes in query
-	escapedQuery := strings.ReplaceAll(args.Query, "'", "\\'")
-
-	// Build query: search by name, filter to Google Docs, exclude trashed
-	q := fmt.Sprintf("name contains '%s' and mimeType='application/vnd.google-apps.document' and trashed=false", escapedQuery)
+	// Build query: search by name and/or content, filter to Google Docs, exclude trashed
+	q := &driveQuery{}
+	if err := q.search(args.SearchMode, args.Query, args.OrderBy); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	q.equals("mimeType", docsMimeType).boolean("trashed", false)
 
 	// Add date filters
-	if args.ModifiedAfter != "" {
-		q += fmt.Sprintf(" and modifiedTime > '%s'", args.ModifiedAfter)
+	if err := q.timeAfter("modifiedTime", args.ModifiedAfter, "modified_after"); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
 	}
-	if args.ModifiedBefore != "" {
-		q += fmt.Sprintf(" and modifiedTime < '%s'", args.ModifiedBefore)
+	if err := q.timeBefore("modifiedTime", args.ModifiedBefore, "modified_before"); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 	// Add owner filter
 	if args.OwnerEmail != "" {
-		q += fmt.Sprintf(" and '%s' in owners", args.OwnerEmail)
+		q.in(args.OwnerEmail, "owners")
+	}
+	if err := args.DriveQuery.apply(q, false); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	call := d.driveService.Files.List().
-		Context(ctx).
-		Q(q).
-		PageSize(int64(pageSize)).
-		Fields("nextPageToken, files(id, name, createdTime, modifiedTime, webViewLink)").
-		SupportsAllDrives(true).
-		IncludeItemsFromAllDrives(true)
-
-	// Apply pagination
-	if args.PageToken != "" {
-		call = call.PageToken(args.PageToken)
+	response, err := d.listDocs(ctx, request, q.String(), args.OrderBy, args.PageToken, pageSize, args.FetchAll, args.MaxItems)
+	if err != nil {
+		return mcp.NewToolResultError("failed to search documents: " + err.Error()), nil
 	}
-	// Apply sorting
-	if args.OrderBy != "" {
-		call = call.OrderBy(args.OrderBy)
+	if args.SearchMode != "" && args.SearchMode != "name" {
+		d.addSnippets(ctx, response.Results, args.Query)
 	}
 
-	fileList, err := call.Do()
+	data, err := types.MarshalResponse(response)
 	if err != nil {
-		return mcp.NewToolResultError("failed to search documents: " + err.Error()), nil
+		return mcp.NewToolResultError("failed to marshal response: " + err.Error()), nil
 	}
+	return mcp.NewToolResultText(data), nil
+}
 
-	results := make([]DocsSearchResult, 0, len(fileList.Files))
-	for _, f := range fileList.Files {
-		results = append(results, DocsSearchResult{
-			ID:    f.Id,
-			Title: f.Name,
-		})
+// listDocs runs a Drive files query and converts the results. If fetchAll is set, it follows
+// page tokens until maxItems results have been collected.
+func (d *DocsTools) listDocs(ctx context.Context, request mcp.CallToolRequest, q, orderBy, pageToken string, pageSize int, fetchAll bool, maxItems int) (DocsSearchResponse, error) {
+	fields := "nextPageToken, files(id, name, createdTime, modifiedTime, webViewLink, owners(emailAddress))"
+	if types.GlobalPolicy.Docs.Enabled() {
+		fields = "nextPageToken, files(id, name, createdTime, modifiedTime, webViewLink, " + docsPolicyFields + ")"
+	}
+
+	fetch := func(ctx context.Context, pageToken string, pageSize int) ([]DocsSearchResult, string, error) {
+		call := d.driveService.Files.List().
+			Context(ctx).
+			Q(q).
+			PageSize(int64(pageSize)).
+			Fields(googleapi.Field(fields)).
+			SupportsAllDrives(true).
+			IncludeItemsFromAllDrives(true)
+
+		// Apply pagination
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		// Apply sorting
+		if orderBy != "" {
+			call = call.OrderBy(orderBy)
+		}
+
+		fileList, err := call.Do()
+		if err != nil {
+			return nil, "", err
+		}
+
+		results := make([]DocsSearchResult, 0, len(fileList.Files))
+		for _, f := range fileList.Files {
+			// Drop documents denied by the access policy
+			if err := d.checkFilePolicy(ctx, f); errors.Is(err, types.ErrPolicyDenied) {
+				continue
+			} else if err != nil {
+				return nil, "", err
+			}
+			owners := make([]string, 0, len(f.Owners))
+			for _, o := range f.Owners {
+				owners = append(owners, o.EmailAddress)
+			}
+			results = append(results, DocsSearchResult{
+				ID:           f.Id,
+				Title:        f.Name,
+				ModifiedTime: f.ModifiedTime,
+				Owners:       owners,
+				WebViewLink:  f.WebViewLink,
+			})
+		}
+		return results, fileList.NextPageToken, nil
 	}
 
-	response := DocsSearchResponse{
-		Results:       results,
-		NextPageToken: fileList.NextPageToken,
+	if !fetchAll {
+		results, next, err := fetch(ctx, pageToken, pageSize)
+		if err != nil {
+			return DocsSearchResponse{}, err
+		}
+		return DocsSearchResponse{Results: results, NextPageToken: next}, nil
 	}
 
-	data, err := types.MarshalResponse(response)
+	paged, err := fetchAllPages(ctx, pageToken, pageSize, maxItems, types.NewProgress(ctx, request), fetch)
 	if err != nil {
-		return mcp.NewToolResultError("failed to marshal response: " + err.Error()), nil
+		return DocsSearchResponse{}, err
 	}
-	return mcp.NewToolResultText(data), nil
+	return DocsSearchResponse{
+		Results:       paged.Items,
+		NextPageToken: paged.NextPageToken,
+		Truncated:     paged.Truncated,
+	}, nil
+}
+
+const (
+	// maxSnippetResults is the number of content search results that get snippets.
+	// Drive doesn't return match context, so each snippet costs an export request.
+	maxSnippetResults = 10
+	// maxSnippetSourceBytes caps how much of each document is read to find a snippet.
+	maxSnippetSourceBytes = 1 << 20
+	// snippetContext is the number of bytes of context shown on each side of a match.
+	snippetContext = 80
+)
+
+// addSnippets fills in Snippet for the first maxSnippetResults results by exporting
+// each document as plain text and locating the query. Snippets are best effort;
+// documents that can't be exported are left without one.
+func (d *DocsTools) addSnippets(ctx context.Context, results []DocsSearchResult, query string) {
+	query = foldCase(query)
+	for i := range results[:min(len(results), maxSnippetResults)] {
+		if err := types.GlobalRateLimiter.Wait(ctx); err != nil {
+			return
+		}
+		resp, err := d.driveService.Files.Export(results[i].ID, "text/plain").Context(ctx).Download()
+		if err != nil {
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxSnippetSourceBytes))
+		resp.Body.Close()
+		if err != nil {
+			continue
+		}
+		results[i].Snippet = sanitizeUntrustedField("snippet", makeSnippet(string(data), query), &results[i].Warnings)
+	}
+}
+
+// makeSnippet returns the text around the first case-insensitive match of query,
+// or of its first matching word, with the match in **bold**. query must already
+// be case folded with foldCase. It returns "" if nothing matches.
+func makeSnippet(text, query string) string {
+	folded := foldCase(text)
+	var loc []int
+	for _, term := range append([]string{query}, strings.Fields(query)...) {
+		if i := strings.Index(folded, term); term != "" && i >= 0 {
+			loc = []int{i, i + len(term)}
+			break
+		}
+	}
+	if loc == nil {
+		return ""
+	}
+
+	start, end := max(loc[0]-snippetContext, 0), min(loc[1]+snippetContext, len(text))
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+
+	snippet := text[start:loc[0]] + "**" + text[loc[0]:loc[1]] + "**" + text[loc[1]:end]
+	snippet = strings.Join(strings.Fields(snippet), " ")
+	if start > 0 {
+		snippet = "..." + snippet
+	}
+	if end < len(text) {
+		snippet += "..."
+	}
+	return snippet
+}
+
+// foldCase lowercases s for case-insensitive matching. Runes whose lowercase form
+// has a different UTF-8 length, and invalid bytes, are kept as they are, so byte
+// offsets in the result are also valid in s.
+func foldCase(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if lower := unicode.ToLower(r); r != utf8.RuneError && utf8.RuneLen(lower) == size {
+			sb.WriteRune(lower)
+		} else {
+			sb.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return sb.String()
 }
 
 // DocsGetContentResponse represents the structured response for document content.
 type DocsGetContentResponse struct {
-	DocID    string            `json:"docId"`
-	DocTitle string            `json:"docTitle"`
-	Tabs     []DocsTabContent  `json:"tabs"`
+	DocID    string           `json:"docId"`
+	DocTitle string           `json:"docTitle"`
+	Tabs     []DocsTabContent `json:"tabs"`
+	Comments []DocsComment    `json:"comments,omitempty"` // Inlined comments whose quoted text wasn't found
 }
 
 // DocsTabContent represents a single tab's content.
 type DocsTabContent struct {
-	TabID       string `json:"tabId"`
-	TabTitle    string `json:"tabTitle"`
-	TabMarkdown string `json:"tabMarkdown"`
+	TabID       string   `json:"tabId"`
+	TabTitle    string   `json:"tabTitle"`
+	TabMarkdown string   `json:"tabMarkdown"`
+	Warnings    []string `json:"warnings,omitempty"` // Untrusted content findings (MCP_HARDEN_UNTRUSTED)
+
+	images []docImage // Images referenced in TabMarkdown, for include_images
+}
+
+// docImage is an image or drawing referenced from converted Markdown.
+type docImage struct {
+	ObjectID   string
+	Alt        string
+	ContentURI string
 }
 
 // GetContentTool returns the tool definition for fetching document content.
@@ -225,7 +473,11 @@ func (d *DocsTools) GetContentTool() mcp.Tool {
 	return mcp.NewTool("docs_get_content",
 		mcp.WithDescription(`Retrieves a Google Doc and converts its content to Markdown.
 
-Supports multi-tab documents. Each tab's content is converted to well-formatted Markdown with proper heading levels, lists, tables, links, and text formatting (bold, italic, strikethrough).
+Supports multi-tab documents. Each tab's content is converted to well-formatted Markdown with proper heading levels, lists, tables, links, and text formatting (bold, italic, strikethrough). Markdown metacharacters in document text are escaped. Suggested edits are marked with CriticMarkup ({++added++}, {--removed--}) unless a preview mode is selected with suggestions. Footnotes are rendered as Markdown footnotes ([^1]) at the end of each tab, and images as Markdown image references. Smart chips are rendered as "@Name <email>" for people, Markdown links for rich links, and ISO 8601 dates for date chips.
+
+For large documents, use tab_id or tab_title to return specific tabs and heading to return a single section (see docs_get_outline).
+
+Set include_images to also return the images themselves as image content blocks (size-capped) for multimodal models.
 
 Returns a JSON object:
   - docId: The document ID
@@ -238,17 +490,77 @@ Returns a JSON object:
 			mcp.Required(),
 			mcp.Description("The document ID (from the URL or docs_search results)"),
 		),
+		mcp.WithBoolean("include_headers_footers",
+			mcp.Description("Include page headers and footers in each tab's Markdown (default false)"),
+		),
+		mcp.WithBoolean("inline_html",
+			mcp.Description("Render underline, superscript/subscript, small caps, and highlighted text as inline HTML (<u>, <sup>, <sub>, <mark>, ...) instead of plain text (default false)"),
+		),
+		mcp.WithBoolean("html_tables",
+			mcp.Description("Render tables with merged cells or nested tables as HTML tables, which Markdown can't represent (default false)"),
+		),
+		mcp.WithString("tab_id",
+			mcp.Description("Only return the tab with this ID (from a previous docs_get_content or docs_get_outline call)"),
+		),
+		mcp.WithString("tab_title",
+			mcp.Description("Only return tabs with this title (case-insensitive)"),
+		),
+		mcp.WithString("heading",
+			mcp.Description("Only return the section under this heading, up to the next heading of the same or higher level. Matches the heading text (case-insensitive) or heading ID (e.g. h.abc123)"),
+		),
+		mcp.WithNumber("heading_offset",
+			mcp.Description("Shift heading levels down by this many (e.g. 2 renders Heading 1 as ###; default 0)"),
+			mcp.Min(0),
+			mcp.Max(5),
+		),
+		mcp.WithString("suggestions",
+			mcp.Description(`How to show suggested edits: "inline" marks them with CriticMarkup ({++added++}, {--removed--}; requires permission to view suggestions), "accepted" previews the document with all suggestions accepted, "original" previews it with all suggestions rejected. Defaults to inline markup for editors and the original text otherwise`),
+			mcp.Enum("inline", "accepted", "original"),
+		),
+		mcp.WithString("comments",
+			mcp.Description(`Inline open comments and their replies next to the text they quote: "footnotes" adds footnote references, "blockquotes" adds a blockquote after the paragraph. Comments whose text can't be found are listed separately`),
+			mcp.Enum("footnotes", "blockquotes"),
+		),
+		mcp.WithBoolean("include_images",
+			mcp.Description(fmt.Sprintf("Return up to %d inline images as image content blocks (default false)", maxDocImages)),
+		),
+		mcp.WithNumber("max_image_bytes",
+			mcp.Description(fmt.Sprintf("Skip images larger than this many bytes when include_images is set (default %d)", defaultMaxImageBytes)),
+			mcp.Min(1),
+			mcp.Max(maxImageBytesLimit),
+		),
 	)
 }
 
+// suggestionsViewModes maps the suggestions argument to a Docs API SuggestionsViewMode.
+var suggestionsViewModes = map[string]string{
+	"":         "DEFAULT_FOR_CURRENT_ACCESS",
+	"inline":   "SUGGESTIONS_INLINE",
+	"accepted": "PREVIEW_SUGGESTIONS_ACCEPTED",
+	"original": "PREVIEW_WITHOUT_SUGGESTIONS",
+}
+
 // GetContentHandler handles docs_get_content tool calls.
 func (d *DocsTools) GetContentHandler(ctx context.Context, request mcp.CallToolRequest, args DocsGetContentRequest) (*mcp.CallToolResult, error) {
 	if args.DocumentID == "" {
 		return mcp.NewToolResultError("document_id is required"), nil
 	}
 
+	viewMode, ok := suggestionsViewModes[args.Suggestions]
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid suggestions mode %q: must be inline, accepted, or original", args.Suggestions)), nil
+	}
+	if !commentInlineStyles[args.Comments] {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid comments style %q: must be footnotes or blockquotes", args.Comments)), nil
+	}
+
+	if err := d.checkDocumentPolicy(ctx, args.DocumentID); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	doc, err := d.docsService.Documents.Get(args.DocumentID).
 		IncludeTabsContent(true).
+		SuggestionsViewMode(viewMode).
 		Context(ctx).
 		Do()
 	if err != nil {
@@ -262,25 +574,165 @@ func (d *DocsTools) GetContentHandler(ctx context.Context, request mcp.CallToolR
 		Tabs:     []DocsTabContent{},
 	}
 
+	opts := markdownOptions{
+		IncludeHeadersFooters: args.IncludeHeadersFooters,
+		InlineHTML:            args.InlineHTML,
+		HTMLTables:            args.HTMLTables,
+		Heading:               strings.TrimSpace(args.Heading),
+		HeadingOffset:         max(args.HeadingOffset, 0),
+	}
+	if args.Comments != "" {
+		comments, err := d.listOpenComments(ctx, args.DocumentID)
+		if err != nil {
+			return mcp.NewToolResultError("failed to get comments: " + err.Error()), nil
+		}
+		opts.Comments = newCommentAnchors(args.Comments, comments)
+	}
+
 	// Process all tabs (with recursive child tab support)
 	if len(doc.Tabs) > 0 {
-		response.Tabs = collectAllTabs(doc.Tabs, doc.Title)
-	} else if doc.Body != nil {
+		match := func(tab *docs.Tab) bool {
+			if args.TabID != "" && tab.TabProperties.TabId != args.TabID {
+				return false
+			}
+			if args.TabTitle != "" && !strings.EqualFold(strings.TrimSpace(tab.TabProperties.Title), strings.TrimSpace(args.TabTitle)) {
+				return false
+			}
+			return opts.Heading == "" || findSection(tab.DocumentTab.Body, opts.Heading) != nil
+		}
+		response.Tabs, err = collectAllTabs(ctx, doc.Tabs, doc.Title, opts, match, types.NewProgress(ctx, request))
+		if err != nil {
+			return mcp.NewToolResultError("failed to convert document: " + err.Error()), nil
+		}
+	} else if doc.Body != nil && (opts.Heading == "" || findSection(doc.Body, opts.Heading) != nil) {
 		// Fallback for legacy single-tab documents
-		var content strings.Builder
-		extractMarkdownContent(&content, doc.Body.Content, nil, 0)
+		markdown, images := convertDocumentTab(legacyDocumentTab(doc), opts)
 		response.Tabs = append(response.Tabs, DocsTabContent{
 			TabID:       "",
 			TabTitle:    doc.Title,
-			TabMarkdown: normalizeNewlines(content.String()),
+			TabMarkdown: markdown,
+			images:      images,
+		})
+	}
+
+	if len(response.Tabs) == 0 && (args.TabID != "" || args.TabTitle != "" || opts.Heading != "") {
+		return mcp.NewToolResultError("no tab matches the given tab_id, tab_title, and heading"), nil
+	}
+
+	// Comments outside a filtered tab or section are expected to be missing
+	if opts.Comments != nil && args.TabID == "" && args.TabTitle == "" && opts.Heading == "" {
+		response.Comments = opts.Comments.unplaced()
+		// Placed comments are hardened as part of the tab Markdown below
+		for i := range response.Comments {
+			sanitizeComment(&response.Comments[i])
+		}
+	}
+
+	// Remember the whole document as rendered by docs_diff, so it can show later changes
+	if args.TabID == "" && args.TabTitle == "" && args.Suggestions == "" && opts == (markdownOptions{}) {
+		d.swapSnapshot(args.DocumentID, docSnapshot{
+			markdown:   snapshotMarkdown(response.Tabs),
+			revisionID: doc.RevisionId,
+			taken:      time.Now(),
 		})
 	}
 
+	// Document text may come from anyone with edit access
+	for i := range response.Tabs {
+		tab := &response.Tabs[i]
+		tab.TabMarkdown = wrapUntrusted("docs:"+args.DocumentID+"/"+tab.TabID, tab.TabMarkdown, &tab.Warnings)
+	}
+
 	data, err := types.MarshalResponse(response)
 	if err != nil {
 		return mcp.NewToolResultError("failed to marshal response: " + err.Error()), nil
 	}
-	return mcp.NewToolResultText(data), nil
+	result := mcp.NewToolResultText(data)
+
+	if args.IncludeImages {
+		maxBytes := args.MaxImageBytes
+		if maxBytes <= 0 {
+			maxBytes = defaultMaxImageBytes
+		}
+		result.Content = append(result.Content, d.fetchImages(ctx, response.Tabs, min(maxBytes, maxImageBytesLimit))...)
+	}
+	return result, nil
+}
+
+const (
+	// defaultMaxImageBytes is the default per-image size cap for include_images.
+	defaultMaxImageBytes = 1 << 20
+	// maxImageBytesLimit is the largest allowed max_image_bytes.
+	maxImageBytesLimit = 5 << 20
+	// maxDocImages caps the number of images returned by include_images.
+	maxDocImages = 20
+	// maxTotalImageBytes caps the combined size of images returned by include_images.
+	maxTotalImageBytes = 10 << 20
+)
+
+// fetchImages downloads images referenced by the converted tabs and returns them as
+// MCP content blocks, each preceded by a caption. Images that fail to download or
+// exceed the size caps are noted in the caption and skipped.
+func (d *DocsTools) fetchImages(ctx context.Context, tabs []DocsTabContent, maxBytes int) []mcp.Content {
+	var content []mcp.Content
+	count, total := 0, 0
+	for _, tab := range tabs {
+		for _, img := range tab.images {
+			if ctx.Err() != nil || count >= maxDocImages || total >= maxTotalImageBytes {
+				return content
+			}
+			if img.ContentURI == "" {
+				continue
+			}
+
+			caption := fmt.Sprintf("Image %s (tab %q): %s", img.ObjectID, tab.TabTitle, img.Alt)
+			data, mimeType, err := d.downloadImage(ctx, img.ContentURI, min(maxBytes, maxTotalImageBytes-total))
+			if err != nil {
+				content = append(content, mcp.NewTextContent(caption+" [skipped: "+err.Error()+"]"))
+				continue
+			}
+			content = append(content,
+				mcp.NewTextContent(caption),
+				mcp.NewImageContent(base64.StdEncoding.EncodeToString(data), mimeType),
+			)
+			count++
+			total += len(data)
+		}
+	}
+	return content
+}
+
+// downloadImage fetches an image content URI, failing if it is larger than maxBytes.
+func (d *DocsTools) downloadImage(ctx context.Context, uri string, maxBytes int) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("download failed: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, int64(maxBytes)+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) > maxBytes {
+		return nil, "", fmt.Errorf("larger than %s", formatSize(int64(maxBytes)))
+	}
+
+	mimeType := resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(mimeType, "image/") {
+		mimeType = http.DetectContentType(data)
+	}
+	if !strings.HasPrefix(mimeType, "image/") {
+		return nil, "", fmt.Errorf("not an image (%s)", mimeType)
+	}
+	return data, strings.TrimSpace(strings.Split(mimeType, ";")[0]), nil
 }
 
 // normalizeNewlines collapses runs of 3+ newlines down to 2 (one blank line).
@@ -289,155 +741,529 @@ func normalizeNewlines(s string) string {
 }
 
 // collectAllTabs recursively collects all tabs and their children into DocsTabContent slices.
-func collectAllTabs(tabs []*docs.Tab, docTitle string) []DocsTabContent {
+// It reports one progress step per converted tab and stops early if ctx is cancelled.
+func collectAllTabs(ctx context.Context, tabs []*docs.Tab, docTitle string, opts markdownOptions, match func(tab *docs.Tab) bool, progress *types.Progress) ([]DocsTabContent, error) {
 	var result []DocsTabContent
+	total := float64(countTabs(tabs))
+	visited := 0
+
+	err := walkTabs(ctx, tabs, func(tab *docs.Tab) error {
+		visited++
+		if match != nil && !match(tab) {
+			return nil
+		}
+		tabTitle := tab.TabProperties.Title
+		if tabTitle == "" {
+			tabTitle = docTitle
+		}
+
+		markdown, images := convertDocumentTab(tab.DocumentTab, opts)
+		result = append(result, DocsTabContent{
+			TabID:       tab.TabProperties.TabId,
+			TabTitle:    tabTitle,
+			TabMarkdown: markdown,
+			images:      images,
+		})
+		progress.Report(float64(visited), total, "converted tab "+tabTitle)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
 
+// walkTabs calls fn for every tab with document content, depth-first through child tabs.
+// It returns ctx.Err() as soon as the context is cancelled.
+func walkTabs(ctx context.Context, tabs []*docs.Tab, fn func(tab *docs.Tab) error) error {
 	for _, tab := range tabs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if tab.TabProperties != nil && tab.DocumentTab != nil {
-			tabTitle := tab.TabProperties.Title
-			if tabTitle == "" {
-				tabTitle = docTitle
-			}
-
-			// Extract markdown content for this tab
-			var content strings.Builder
-			if tab.DocumentTab.Body != nil {
-				extractMarkdownContent(&content, tab.DocumentTab.Body.Content, tab.DocumentTab.Lists, 0)
+			if err := fn(tab); err != nil {
+				return err
 			}
-
-			result = append(result, DocsTabContent{
-				TabID:       tab.TabProperties.TabId,
-				TabTitle:    tabTitle,
-				TabMarkdown: normalizeNewlines(content.String()),
-			})
 		}
 
 		// Process child tabs recursively
 		if len(tab.ChildTabs) > 0 {
-			result = append(result, collectAllTabs(tab.ChildTabs, docTitle)...)
+			if err := walkTabs(ctx, tab.ChildTabs, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// countTabs returns the number of tabs with document content, including child tabs.
+func countTabs(tabs []*docs.Tab) int {
+	n := 0
+	for _, tab := range tabs {
+		if tab.TabProperties != nil && tab.DocumentTab != nil {
+			n++
 		}
+		n += countTabs(tab.ChildTabs)
 	}
+	return n
+}
+
+// markdownOptions controls optional parts of the Docs to Markdown conversion.
+type markdownOptions struct {
+	IncludeHeadersFooters bool   // Render page headers before and footers after the body
+	InlineHTML            bool   // Render underline, super/subscript, small caps, and highlights as HTML
+	HTMLTables            bool   // Render tables with merged cells or nested tables as HTML
+	Heading               string // Only convert the body section under this heading (text or heading ID)
+	HeadingOffset         int    // Shift body heading levels down (e.g., 2 means HEADING_1 becomes ###)
 
-	return result
+	// Comments are open comments to inline next to the text they quote.
+	Comments *commentAnchors
 }
 
-// extractMarkdownContent extracts text from document structural elements and converts to markdown.
-// headingOffset adjusts heading levels (e.g., 2 means HEADING_1 becomes ###).
-func extractMarkdownContent(sb *strings.Builder, elements []*docs.StructuralElement, lists map[string]docs.List, headingOffset int) {
-	for _, elem := range elements {
-		if elem.Paragraph != nil {
-			processParagraph(sb, elem.Paragraph, lists, headingOffset)
+// docContext carries tab-level data needed while converting structural elements.
+type docContext struct {
+	opts              markdownOptions
+	lists             map[string]docs.List
+	footnotes         map[string]docs.Footnote
+	inlineObjects     map[string]docs.InlineObject
+	positionedObjects map[string]docs.PositionedObject
+
+	// images lists images referenced so far, in document order.
+	images []docImage
+
+	// listCounters tracks the current item number per list ID and nesting level.
+	listCounters map[string][]int64
+	// listWidths tracks the marker width of the last item per list ID and nesting level.
+	listWidths map[string][]int
+
+	// footnoteIDs lists referenced footnotes in order of first reference.
+	footnoteIDs    []string
+	footnoteLabels map[string]string
+
+	// commentNotes lists comments placed as footnotes, in order of placement.
+	commentNotes []*commentAnchor
+	// pendingComments are comments waiting to be written as blockquotes after the
+	// current block, indented by commentIndent.
+	pendingComments []*commentAnchor
+	commentIndent   string
+	// tableDepth is the number of tables being converted, used to hold blockquotes
+	// until the outermost table ends.
+	tableDepth int
+}
+
+// newDocContext creates a docContext for a document tab.
+func newDocContext(tab *docs.DocumentTab, opts markdownOptions) *docContext {
+	return &docContext{
+		opts:              opts,
+		lists:             tab.Lists,
+		footnotes:         tab.Footnotes,
+		inlineObjects:     tab.InlineObjects,
+		positionedObjects: tab.PositionedObjects,
+		footnoteLabels:    map[string]string{},
+		listCounters:      map[string][]int64{},
+		listWidths:        map[string][]int{},
+	}
+}
+
+// legacyDocumentTab wraps the content of a document without tabs as a DocumentTab.
+func legacyDocumentTab(doc *docs.Document) *docs.DocumentTab {
+	return &docs.DocumentTab{
+		Body:              doc.Body,
+		DocumentStyle:     doc.DocumentStyle,
+		Footers:           doc.Footers,
+		Footnotes:         doc.Footnotes,
+		Headers:           doc.Headers,
+		InlineObjects:     doc.InlineObjects,
+		Lists:             doc.Lists,
+		PositionedObjects: doc.PositionedObjects,
+	}
+}
+
+// convertDocumentTab converts a tab's body, footnotes, and optionally its headers
+// and footers to Markdown. It also returns the images referenced in the Markdown.
+func convertDocumentTab(tab *docs.DocumentTab, opts markdownOptions) (string, []docImage) {
+	dc := newDocContext(tab, opts)
+
+	var sb strings.Builder
+	if opts.IncludeHeadersFooters {
+		for _, id := range headerFooterIDs(tab.DocumentStyle, tab.Headers, true) {
+			writeHeaderFooter(&sb, dc, "header", tab.Headers[id].Content)
 		}
-		if elem.Table != nil {
-			processTable(sb, elem.Table, lists, headingOffset)
+	}
+	if tab.Body != nil {
+		content := tab.Body.Content
+		if opts.Heading != "" {
+			content = findSection(tab.Body, opts.Heading)
 		}
-		if elem.SectionBreak != nil {
-			// Skip section breaks at the start of the document (nothing written yet or only whitespace)
-			if sb.Len() > 0 && strings.TrimSpace(sb.String()) != "" {
-				sb.WriteString("\n---\n\n")
-			}
+		extractMarkdownContent(&sb, content, dc, opts.HeadingOffset)
+	}
+	if opts.IncludeHeadersFooters {
+		for _, id := range headerFooterIDs(tab.DocumentStyle, tab.Footers, false) {
+			writeHeaderFooter(&sb, dc, "footer", tab.Footers[id].Content)
 		}
 	}
+	writeFootnotes(&sb, dc)
+	writeCommentNotes(&sb, dc)
+
+	return normalizeNewlines(sb.String()), dc.images
 }
 
-// processParagraph converts a paragraph to markdown.
-// headingOffset adjusts heading levels (e.g., 2 means HEADING_1 becomes ###).
-func processParagraph(sb *strings.Builder, para *docs.Paragraph, lists map[string]docs.List, headingOffset int) {
-	if para == nil {
-		return
+// imageMarkdown renders an embedded object as a Markdown image reference and
+// records it for include_images. Drawings without a rendered image become a placeholder.
+func (dc *docContext) imageMarkdown(objectID string, obj *docs.EmbeddedObject) string {
+	if obj == nil {
+		return ""
 	}
 
-	// Determine heading level from paragraph style
-	headingLevel := 0
-	if para.ParagraphStyle != nil {
-		switch para.ParagraphStyle.NamedStyleType {
-		case "TITLE":
-			headingLevel = 1
-		case "HEADING_1":
-			headingLevel = 1
-		case "HEADING_2":
-			headingLevel = 2
-		case "HEADING_3":
-			headingLevel = 3
-		case "HEADING_4":
-			headingLevel = 4
-		case "HEADING_5":
-			headingLevel = 5
-		case "HEADING_6":
-			headingLevel = 6
-		}
+	alt := strings.TrimSpace(obj.Description)
+	if alt == "" {
+		alt = strings.TrimSpace(obj.Title)
 	}
+	alt = strings.NewReplacer("[", "(", "]", ")", "\n", " ").Replace(alt)
 
-	// Apply offset and cap at 6
-	headingPrefix := ""
-	if headingLevel > 0 {
-		adjustedLevel := min(headingLevel+headingOffset, 6)
-		headingPrefix = strings.Repeat("#", adjustedLevel) + " "
+	var uri string
+	if obj.ImageProperties != nil {
+		uri = obj.ImageProperties.ContentUri
 	}
-
-	// Handle bullet points
-	bulletPrefix := ""
-	if para.Bullet != nil {
-		nestingLevel := 0
-		if para.Bullet.NestingLevel > 0 {
-			nestingLevel = int(para.Bullet.NestingLevel)
+	if uri == "" {
+		kind := "Image"
+		if obj.EmbeddedDrawingProperties != nil {
+			kind = "Drawing"
 		}
-		indent := strings.Repeat("  ", nestingLevel)
-
-		// Check if it's an ordered list
-		isOrdered := false
-		if lists != nil && para.Bullet.ListId != "" {
-			if list, ok := lists[para.Bullet.ListId]; ok {
-				if list.ListProperties != nil && len(list.ListProperties.NestingLevels) > nestingLevel {
-					nl := list.ListProperties.NestingLevels[nestingLevel]
-					if nl.GlyphType == "DECIMAL" || nl.GlyphType == "ALPHA" || nl.GlyphType == "ROMAN" {
-						isOrdered = true
-					}
-				}
-			}
+		if alt == "" {
+			return "*[" + kind + "]*"
 		}
+		return "*[" + kind + ": " + alt + "]*"
+	}
+
+	dc.images = append(dc.images, docImage{ObjectID: objectID, Alt: alt, ContentURI: uri})
+	if alt == "" {
+		alt = "image"
+	}
+	md := "![" + alt + "](" + uri
+	if title := strings.TrimSpace(obj.Title); title != "" && title != alt {
+		md += ` "` + strings.ReplaceAll(title, `"`, `'`) + `"`
+	}
+	return md + ")"
+}
 
-		if isOrdered {
-			bulletPrefix = indent + "1. "
+// headerFooterIDs returns the IDs of the headers (or footers) in display order:
+// default, first page, even page, then any others used by individual sections.
+func headerFooterIDs[T any](style *docs.DocumentStyle, items map[string]T, headers bool) []string {
+	var ordered []string
+	if style != nil {
+		if headers {
+			ordered = []string{style.DefaultHeaderId, style.FirstPageHeaderId, style.EvenPageHeaderId}
 		} else {
-			bulletPrefix = indent + "- "
+			ordered = []string{style.DefaultFooterId, style.FirstPageFooterId, style.EvenPageFooterId}
 		}
 	}
 
-	// Build paragraph content with inline formatting
-	var paraContent strings.Builder
-	for _, e := range para.Elements {
-		if e.TextRun != nil {
-			text := e.TextRun.Content
-			if text == "" {
-				continue
-			}
-
-			// Apply inline formatting
-			formatted := formatTextRun(e.TextRun)
-			paraContent.WriteString(formatted)
+	seen := map[string]bool{}
+	var ids []string
+	for _, id := range ordered {
+		if _, ok := items[id]; ok && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
 		}
 	}
+	rest := make([]string, 0, len(items))
+	for id := range items {
+		if !seen[id] {
+			rest = append(rest, id)
+		}
+	}
+	sort.Strings(rest)
+	return append(ids, rest...)
+}
 
-	content := paraContent.String()
+// writeHeaderFooter writes a page header or footer delimited by HTML comments.
+func writeHeaderFooter(sb *strings.Builder, dc *docContext, kind string, content []*docs.StructuralElement) {
+	var hf strings.Builder
+	extractMarkdownContent(&hf, content, dc, 0)
+	text := strings.TrimSpace(normalizeNewlines(hf.String()))
+	if text == "" {
+		return
+	}
+	if sb.Len() > 0 && !strings.HasSuffix(sb.String(), "\n\n") {
+		sb.WriteString("\n")
+	}
+	sb.WriteString("<!-- " + kind + " -->\n")
+	sb.WriteString(text)
+	sb.WriteString("\n<!-- /" + kind + " -->\n\n")
+}
 
-	// Skip empty paragraphs (but keep newlines for spacing)
-	trimmed := strings.TrimSpace(content)
-	if trimmed == "" {
-		if !strings.HasSuffix(sb.String(), "\n\n") {
-			sb.WriteString("\n")
-		}
+// footnoteLabel returns the Markdown label for a footnote, recording it for output.
+func (dc *docContext) footnoteLabel(ref *docs.FootnoteReference) string {
+	if label, ok := dc.footnoteLabels[ref.FootnoteId]; ok {
+		return label
+	}
+	label := ref.FootnoteNumber
+	if label == "" {
+		label = strconv.Itoa(len(dc.footnoteIDs) + 1)
+	}
+	dc.footnoteIDs = append(dc.footnoteIDs, ref.FootnoteId)
+	dc.footnoteLabels[ref.FootnoteId] = label
+	return label
+}
+
+// writeFootnotes appends the bodies of all referenced footnotes as Markdown footnote definitions.
+func writeFootnotes(sb *strings.Builder, dc *docContext) {
+	if len(dc.footnoteIDs) == 0 {
 		return
 	}
+	if !strings.HasSuffix(sb.String(), "\n\n") {
+		sb.WriteString("\n")
+	}
 
-	// Write with appropriate prefix
-	if headingPrefix != "" {
-		// Ensure blank line before heading (if not at document start)
+	// Footnotes can reference other footnotes, so the list may grow while iterating
+	for i := 0; i < len(dc.footnoteIDs); i++ {
+		id := dc.footnoteIDs[i]
+		var fn strings.Builder
+		if footnote, ok := dc.footnotes[id]; ok {
+			extractMarkdownContent(&fn, footnote.Content, dc, 0)
+		}
+		text := strings.TrimSpace(normalizeNewlines(fn.String()))
+
+		sb.WriteString("[^")
+		sb.WriteString(dc.footnoteLabels[id])
+		sb.WriteString("]: ")
+		// Indent continuation lines so multi-paragraph footnotes stay attached
+		sb.WriteString(indentContinuation(text, "    "))
+		sb.WriteString("\n")
+	}
+}
+
+// indentContinuation indents every non-empty line after the first.
+func indentContinuation(s, indent string) string {
+	lines := strings.Split(s, "\n")
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = indent + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// extractMarkdownContent extracts text from document structural elements and converts to markdown.
+// headingOffset adjusts heading levels (e.g., 2 means HEADING_1 becomes ###).
+func extractMarkdownContent(sb *strings.Builder, elements []*docs.StructuralElement, dc *docContext, headingOffset int) {
+	for i := 0; i < len(elements); i++ {
+		elem := elements[i]
+		if isCodeParagraph(elem.Paragraph) {
+			// Consecutive monospace paragraphs form a single code block
+			var paras []*docs.Paragraph
+			for ; i < len(elements) && isCodeParagraph(elements[i].Paragraph); i++ {
+				paras = append(paras, elements[i].Paragraph)
+				dc.placeComments(elements[i].Paragraph, "", true)
+			}
+			i--
+			writeCodeBlock(sb, paras)
+			if dc.tableDepth == 0 {
+				dc.commentIndent = ""
+			}
+			dc.flushComments(sb)
+			continue
+		}
+		if elem.Paragraph != nil {
+			processParagraph(sb, elem.Paragraph, dc, headingOffset)
+		}
+		if elem.Table != nil {
+			if paras, ok := codeBlockTable(elem.Table); ok {
+				writeCodeBlock(sb, paras)
+			} else {
+				processTable(sb, elem.Table, dc, headingOffset)
+			}
+		}
+		if elem.SectionBreak != nil {
+			// Skip section breaks at the start of the document (nothing written yet or only whitespace)
+			if sb.Len() > 0 && strings.TrimSpace(sb.String()) != "" {
+				sb.WriteString("\n---\n\n")
+			}
+		}
+		dc.flushComments(sb)
+	}
+}
+
+// paragraphHeadingLevel returns the heading level (1-6) of a paragraph, or 0 if
+// it isn't a heading. Titles are treated as level 1.
+func paragraphHeadingLevel(para *docs.Paragraph) int {
+	if para == nil || para.ParagraphStyle == nil {
+		return 0
+	}
+	switch para.ParagraphStyle.NamedStyleType {
+	case "TITLE":
+		return 1
+	case "HEADING_1":
+		return 1
+	case "HEADING_2":
+		return 2
+	case "HEADING_3":
+		return 3
+	case "HEADING_4":
+		return 4
+	case "HEADING_5":
+		return 5
+	case "HEADING_6":
+		return 6
+	}
+	return 0
+}
+
+// findSection returns the body elements from the first heading matching heading
+// (by text, case-insensitively, or by heading ID) up to the next heading of the
+// same or higher level. It returns nil if no heading matches.
+func findSection(body *docs.Body, heading string) []*docs.StructuralElement {
+	if body == nil {
+		return nil
+	}
+	headingID := strings.TrimPrefix(heading, "#heading=")
+	for i, elem := range body.Content {
+		level := paragraphHeadingLevel(elem.Paragraph)
+		if level == 0 {
+			continue
+		}
+		text := strings.Join(strings.Fields(asciiTypography(paragraphText(elem.Paragraph))), " ")
+		if elem.Paragraph.ParagraphStyle.HeadingId != headingID && !strings.EqualFold(text, strings.Join(strings.Fields(heading), " ")) {
+			continue
+		}
+		end := i + 1
+		for end < len(body.Content) {
+			if l := paragraphHeadingLevel(body.Content[end].Paragraph); l > 0 && l <= level {
+				break
+			}
+			end++
+		}
+		return body.Content[i:end]
+	}
+	return nil
+}
+
+// processParagraph converts a paragraph to markdown.
+// headingOffset adjusts heading levels (e.g., 2 means HEADING_1 becomes ###).
+func processParagraph(sb *strings.Builder, para *docs.Paragraph, dc *docContext, headingOffset int) {
+	if para == nil {
+		return
+	}
+
+	// Determine heading level from paragraph style
+	headingLevel := paragraphHeadingLevel(para)
+
+	// Apply offset and cap at 6
+	headingPrefix := ""
+	if headingLevel > 0 {
+		adjustedLevel := min(headingLevel+headingOffset, 6)
+		headingPrefix = strings.Repeat("#", adjustedLevel) + " "
+	}
+
+	// Handle bullet points
+	bulletPrefix := ""
+	contentIndent := ""
+	if para.Bullet != nil {
+		nestingLevel := 0
+		if para.Bullet.NestingLevel > 0 {
+			nestingLevel = int(para.Bullet.NestingLevel)
+		}
+
+		marker, isChecklist := dc.listMarker(para.Bullet.ListId, nestingLevel)
+		// A task item's content starts after "- "; the checkbox is part of it
+		width := len(marker)
+		if isChecklist {
+			width = len("- ")
+		}
+		indent := dc.listIndent(para.Bullet.ListId, nestingLevel, width)
+		contentIndent = indent + strings.Repeat(" ", width)
+		if isChecklist {
+			// Docs marks checked items by striking through their text
+			if isStruckThrough(para) {
+				marker = "- [x] "
+				para = withoutStrikethrough(para)
+			} else {
+				marker = "- [ ] "
+			}
+		}
+		bulletPrefix = indent + marker
+	}
+
+	// Build paragraph content with inline formatting
+	var paraContent strings.Builder
+	for _, e := range para.Elements {
+		if e.TextRun != nil {
+			text := e.TextRun.Content
+			if text == "" {
+				continue
+			}
+
+			// Apply inline formatting
+			formatted := formatTextRun(e.TextRun, dc.opts)
+			paraContent.WriteString(suggestionMarkup(formatted, e.TextRun))
+		}
+		if e.FootnoteReference != nil {
+			paraContent.WriteString("[^")
+			paraContent.WriteString(dc.footnoteLabel(e.FootnoteReference))
+			paraContent.WriteString("]")
+		}
+		if e.InlineObjectElement != nil {
+			id := e.InlineObjectElement.InlineObjectId
+			if obj, ok := dc.inlineObjects[id]; ok && obj.InlineObjectProperties != nil {
+				paraContent.WriteString(dc.imageMarkdown(id, obj.InlineObjectProperties.EmbeddedObject))
+			}
+		}
+		if e.Person != nil {
+			paraContent.WriteString(formatPerson(e.Person))
+		}
+		if e.RichLink != nil {
+			paraContent.WriteString(formatRichLink(e.RichLink))
+		}
+		if e.DateElement != nil {
+			paraContent.WriteString(formatDateElement(e.DateElement))
+		}
+	}
+
+	content := paraContent.String()
+
+	// Positioned (floating) objects are anchored to this paragraph; render them after its text
+	var positioned strings.Builder
+	for _, id := range para.PositionedObjectIds {
+		if obj, ok := dc.positionedObjects[id]; ok && obj.PositionedObjectProperties != nil {
+			positioned.WriteString(" ")
+			positioned.WriteString(dc.imageMarkdown(id, obj.PositionedObjectProperties.EmbeddedObject))
+		}
+	}
+	if positioned.Len() > 0 {
+		content = strings.TrimSuffix(content, "\n") + positioned.String() + "\n"
+	}
+
+	// Skip empty paragraphs (but keep newlines for spacing)
+	trimmed := strings.TrimSpace(content)
+	if trimmed == "" {
+		if !strings.HasSuffix(sb.String(), "\n\n") {
+			sb.WriteString("\n")
+		}
+		return
+	}
+
+	content = dc.placeComments(para, content, false)
+	if bulletPrefix != "" && dc.tableDepth == 0 {
+		// Keep blockquoted comments inside the list item
+		dc.commentIndent = contentIndent
+	} else if dc.tableDepth == 0 {
+		dc.commentIndent = ""
+	}
+
+	// Write with appropriate prefix
+	if headingPrefix != "" {
+		// Ensure blank line before heading (if not at document start)
 		if sb.Len() > 0 && !strings.HasSuffix(sb.String(), "\n\n") {
 			sb.WriteString("\n")
 		}
 		// Remove trailing newline for heading, add it after
-		content = strings.TrimSuffix(content, "\n")
+		content = strings.TrimRight(content, " \t\n")
+		// A trailing "#" would be read as a closing heading sequence
+		if strings.HasSuffix(content, "#") && !strings.HasSuffix(content, `\#`) {
+			content = content[:len(content)-1] + `\#`
+		}
 		sb.WriteString(headingPrefix)
 		sb.WriteString(content)
 		sb.WriteString("\n\n")
@@ -449,62 +1275,214 @@ func processParagraph(sb *strings.Builder, para *docs.Paragraph, lists map[strin
 			return
 		}
 		sb.WriteString(bulletPrefix)
-		sb.WriteString(content)
+		sb.WriteString(escapeLineStarts(content))
 		sb.WriteString("\n")
 	} else {
 		// Regular paragraph - ensure proper spacing
 		content = strings.TrimSuffix(content, "\n")
-		sb.WriteString(content)
+		sb.WriteString(escapeLineStarts(content))
 		sb.WriteString("\n\n")
 	}
 }
 
-// formatTextRun applies markdown formatting to a text run.
-func formatTextRun(tr *docs.TextRun) string {
+// checklistGlyphs are glyph symbols Docs uses for checkbox lists.
+var checklistGlyphs = map[string]bool{"☐": true, "☑": true, "☒": true, "□": true, "❏": true, "❑": true}
+
+// listMarker returns the Markdown marker (e.g. "- ", "3. ") for an item at the given
+// nesting level of a list, advancing that list's numbering. Numbering continues
+// across paragraphs for the same list ID, honors start numbers, and resets deeper
+// levels whenever a shallower item appears. Markdown only has decimal list markers,
+// so lettered, Roman, and multi-level ("1.2.") numbering is written as the item's
+// number at its own level. isChecklist reports a checkbox list, for which the caller
+// chooses the checked state.
+func (dc *docContext) listMarker(listID string, level int) (marker string, isChecklist bool) {
+	list, ok := dc.lists[listID]
+	if !ok || list.ListProperties == nil || len(list.ListProperties.NestingLevels) <= level {
+		return "- ", false
+	}
+	nl := list.ListProperties.NestingLevels[level]
+	if checklistGlyphs[nl.GlyphSymbol] {
+		return "- [ ] ", true
+	}
+	if !isOrderedGlyph(nl.GlyphType) {
+		return "- ", false
+	}
+
+	// Advance this level and forget deeper levels so nested lists restart
+	counters := dc.listCounters[listID]
+	for len(counters) <= level {
+		counters = append(counters, 0)
+	}
+	counters = counters[:level+1]
+	if counters[level] == 0 {
+		counters[level] = startNumber(nl)
+	} else {
+		counters[level]++
+	}
+	dc.listCounters[listID] = counters
+	return strconv.FormatInt(counters[level], 10) + ". ", false
+}
+
+// listIndent returns the indentation for an item at the given nesting level of a
+// list: the content column of the last item one level up, which CommonMark needs
+// to nest it. width is the length of the item's own marker, recorded for the items
+// nested in it.
+func (dc *docContext) listIndent(listID string, level, width int) string {
+	widths := dc.listWidths[listID]
+	for len(widths) < level {
+		widths = append(widths, len("- "))
+	}
+	indent := 0
+	for _, w := range widths[:level] {
+		indent += w
+	}
+	dc.listWidths[listID] = append(widths[:level], width)
+	return strings.Repeat(" ", indent)
+}
+
+// isOrderedGlyph reports whether a list glyph type is numbered.
+func isOrderedGlyph(glyphType string) bool {
+	switch glyphType {
+	case "DECIMAL", "ZERO_DECIMAL", "ALPHA", "UPPER_ALPHA", "ROMAN", "UPPER_ROMAN":
+		return true
+	}
+	return false
+}
+
+// startNumber returns the first number of a nesting level (default 1).
+func startNumber(nl *docs.NestingLevel) int64 {
+	if nl.StartNumber > 0 {
+		return nl.StartNumber
+	}
+	return 1
+}
+
+// isStruckThrough reports whether all non-whitespace text in a paragraph is struck through.
+func isStruckThrough(para *docs.Paragraph) bool {
+	found := false
+	for _, e := range para.Elements {
+		if e.TextRun == nil || strings.TrimSpace(e.TextRun.Content) == "" {
+			continue
+		}
+		if e.TextRun.TextStyle == nil || !e.TextRun.TextStyle.Strikethrough {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// withoutStrikethrough returns a shallow copy of a paragraph with strikethrough
+// removed from its text runs, used for checked checklist items.
+func withoutStrikethrough(para *docs.Paragraph) *docs.Paragraph {
+	cp := *para
+	cp.Elements = make([]*docs.ParagraphElement, len(para.Elements))
+	for i, e := range para.Elements {
+		cp.Elements[i] = e
+		if e.TextRun == nil || e.TextRun.TextStyle == nil {
+			continue
+		}
+		style := *e.TextRun.TextStyle
+		style.Strikethrough = false
+		run := *e.TextRun
+		run.TextStyle = &style
+		elem := *e
+		elem.TextRun = &run
+		cp.Elements[i] = &elem
+	}
+	return &cp
+}
+
+// formatPerson renders a person smart chip as "@Name <email>".
+func formatPerson(p *docs.Person) string {
+	if p.PersonProperties == nil {
+		return ""
+	}
+	name, email := p.PersonProperties.Name, p.PersonProperties.Email
+	switch {
+	case name != "" && email != "":
+		return "@" + name + " <" + email + ">"
+	case name != "":
+		return "@" + name
+	default:
+		return "@" + email
+	}
+}
+
+// formatRichLink renders a rich link chip (Drive file, Calendar event, YouTube video, etc.)
+// as a Markdown link using the chip's title.
+func formatRichLink(rl *docs.RichLink) string {
+	if rl.RichLinkProperties == nil || rl.RichLinkProperties.Uri == "" {
+		return ""
+	}
+	title := rl.RichLinkProperties.Title
+	if title == "" {
+		title = rl.RichLinkProperties.Uri
+	}
+	return "[" + escapeMarkdown(title) + "](" + escapeURL(rl.RichLinkProperties.Uri) + ")"
+}
+
+// formatDateElement renders a date chip as an ISO 8601 date, or date and time if the
+// chip shows a time. Falls back to the chip's display text if the timestamp is missing.
+func formatDateElement(de *docs.DateElement) string {
+	props := de.DateElementProperties
+	if props == nil {
+		return ""
+	}
+	t, err := time.Parse(time.RFC3339Nano, props.Timestamp)
+	if err != nil {
+		return props.DisplayText
+	}
+	if props.TimeZoneId != "" {
+		if loc, err := time.LoadLocation(props.TimeZoneId); err == nil {
+			t = t.In(loc)
+		}
+	}
+	switch props.TimeFormat {
+	case "TIME_FORMAT_HOUR_MINUTE", "TIME_FORMAT_HOUR_MINUTE_TIMEZONE":
+		return t.Format("2006-01-02T15:04Z07:00")
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
+// formatTextRun applies markdown formatting to a text run, escaping Markdown
+// metacharacters in its text.
+func formatTextRun(tr *docs.TextRun, opts markdownOptions) string {
 	if tr == nil || tr.Content == "" {
 		return ""
 	}
 
-	text := tr.Content
 	style := tr.TextStyle
 
 	// Replace vertical tabs (U+000B) with double newlines
-	text = strings.ReplaceAll(text, "\u000B", "\n\n")
-
-	// Convert smart typography to ASCII equivalents for compatibility
-	text = strings.ReplaceAll(text, "\u2018", "'")  // left single quote
-	text = strings.ReplaceAll(text, "\u2019", "'")  // right single quote / apostrophe
-	text = strings.ReplaceAll(text, "\u201C", "\"") // left double quote
-	text = strings.ReplaceAll(text, "\u201D", "\"") // right double quote
-	text = strings.ReplaceAll(text, "\u2014", "--") // em dash
+	text := strings.ReplaceAll(tr.Content, "\u000B", "\n\n")
+	text = asciiTypography(text)
 
 	// Don't format whitespace-only content
 	if strings.TrimSpace(text) == "" {
 		return text
 	}
 
-	// Check for link
-	if style != nil && style.Link != nil && style.Link.Url != "" {
-		// Preserve trailing whitespace/newlines
-		trimmed := strings.TrimSpace(text)
-		if trimmed != "" {
-			suffix := text[len(strings.TrimRight(text, " \t\n")):]
-			return fmt.Sprintf("[%s](%s)%s", trimmed, style.Link.Url, suffix)
+	// Keep leading/trailing whitespace (including newlines) outside of markers
+	trimmed := strings.TrimSpace(text)
+	leadingSpace := text[:len(text)-len(strings.TrimLeft(text, " \t\n"))]
+	trailingSpace := text[len(strings.TrimRight(text, " \t\n")):]
+	hasLink := style != nil && style.Link != nil && style.Link.Url != ""
+
+	// Monospace runs become inline code; other styling doesn't apply inside code spans
+	if isMonospace(style) {
+		code := inlineCode(trimmed)
+		if hasLink {
+			code = "[" + code + "](" + escapeURL(style.Link.Url) + ")"
 		}
+		return leadingSpace + code + trailingSpace
 	}
 
-	// Apply bold and italic with trimmed whitespace
-	if style != nil && (style.Bold || style.Italic || style.Strikethrough) {
-		// Extract leading/trailing whitespace (including newlines) to keep outside markers
-		trimmed := strings.TrimSpace(text)
-		if trimmed == "" {
-			return text
-		}
-		leadingSpace := text[:len(text)-len(strings.TrimLeft(text, " \t\n"))]
-		trailingSpace := text[len(strings.TrimRight(text, " \t\n")):]
+	formatted := escapeMarkdown(trimmed)
+	if style != nil {
+		formatted = formatExtendedStyle(formatted, style, hasLink, opts)
 
-		// Apply formatting to trimmed content only
-		formatted := trimmed
 		if style.Bold && style.Italic {
 			formatted = "***" + formatted + "***"
 		} else if style.Bold {
@@ -516,274 +1494,2249 @@ func formatTextRun(tr *docs.TextRun) string {
 		if style.Strikethrough {
 			formatted = "~~" + formatted + "~~"
 		}
+	}
 
-		text = leadingSpace + formatted + trailingSpace
+	if hasLink {
+		formatted = "[" + formatted + "](" + escapeURL(style.Link.Url) + ")"
 	}
 
+	return leadingSpace + formatted + trailingSpace
+}
+
+// formatExtendedStyle applies styles Markdown has no syntax for (underline,
+// superscript/subscript, small caps, highlight). They are rendered as inline HTML
+// when opts.InlineHTML is set and dropped otherwise. Links are underlined by
+// default, so underline is ignored on them.
+func formatExtendedStyle(text string, style *docs.TextStyle, hasLink bool, opts markdownOptions) string {
+	if !opts.InlineHTML {
+		return text
+	}
+	switch style.BaselineOffset {
+	case "SUPERSCRIPT":
+		text = "<sup>" + text + "</sup>"
+	case "SUBSCRIPT":
+		text = "<sub>" + text + "</sub>"
+	}
+	if style.SmallCaps {
+		text = `<span style="font-variant: small-caps">` + text + "</span>"
+	}
+	if style.Underline && !hasLink {
+		text = "<u>" + text + "</u>"
+	}
+	if isHighlighted(style) {
+		text = "<mark>" + text + "</mark>"
+	}
 	return text
 }
 
-// processTable converts a table to markdown.
-func processTable(sb *strings.Builder, table *docs.Table, lists map[string]docs.List, headingOffset int) {
-	if table == nil || len(table.TableRows) == 0 {
-		return
+// isHighlighted reports whether a text style has a non-white background color.
+func isHighlighted(style *docs.TextStyle) bool {
+	if style.BackgroundColor == nil || style.BackgroundColor.Color == nil || style.BackgroundColor.Color.RgbColor == nil {
+		return false
 	}
+	rgb := style.BackgroundColor.Color.RgbColor
+	return rgb.Red < 1 || rgb.Green < 1 || rgb.Blue < 1
+}
 
-	sb.WriteString("\n")
+// markdownEscaper escapes inline Markdown metacharacters.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "~", `\~`,
+)
 
-	for rowIdx, row := range table.TableRows {
-		sb.WriteString("|")
-		for _, cell := range row.TableCells {
-			var cellContent strings.Builder
-			extractMarkdownContent(&cellContent, cell.Content, lists, headingOffset)
-			// Clean up cell content - remove newlines, trim
-			cellText := strings.TrimSpace(cellContent.String())
-			cellText = strings.ReplaceAll(cellText, "\n", " ")
-			sb.WriteString(" ")
-			sb.WriteString(cellText)
-			sb.WriteString(" |")
+// htmlTagStartRe matches a "<" that would start an HTML tag, comment, or autolink.
+var htmlTagStartRe = regexp.MustCompile(`<([A-Za-z/!?])`)
+
+// escapeMarkdown escapes characters in document text that Markdown would otherwise
+// interpret as formatting. Block-level markers are handled by escapeLineStarts and
+// table pipes by processTable.
+func escapeMarkdown(text string) string {
+	text = markdownEscaper.Replace(text)
+	return htmlTagStartRe.ReplaceAllString(text, `\<$1`)
+}
+
+// blockMarkerRe matches text at the start of a line that Markdown would read as a
+// heading, block quote, list item, or thematic break.
+var blockMarkerRe = regexp.MustCompile(`(?m)^([ \t]*)(#{1,6}(?:[ \t]|$)|>|[-+=](?:[ \t]|$)|-{3,}|={3,}|\d{1,9}[.)](?:[ \t]|$))`)
+
+// escapeLineStarts escapes block-level Markdown markers at the start of each line of
+// paragraph text, e.g. "1. " becomes "1\. " and "# " becomes "\# ".
+func escapeLineStarts(text string) string {
+	return blockMarkerRe.ReplaceAllStringFunc(text, func(m string) string {
+		indent := len(m) - len(strings.TrimLeft(m, " \t"))
+		marker := m[indent:]
+		if marker[0] >= '0' && marker[0] <= '9' {
+			i := strings.IndexAny(marker, ".)")
+			return m[:indent] + marker[:i] + `\` + marker[i:]
 		}
-		sb.WriteString("\n")
+		return m[:indent] + `\` + marker
+	})
+}
 
-		// Add header separator after first row
-		if rowIdx == 0 {
-			sb.WriteString("|")
-			for range row.TableCells {
-				sb.WriteString(" --- |")
-			}
-			sb.WriteString("\n")
+// urlEscaper percent-encodes characters that would end a Markdown link destination.
+var urlEscaper = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E")
+
+// escapeURL makes a URL safe to use as a Markdown link destination.
+func escapeURL(u string) string {
+	return urlEscaper.Replace(u)
+}
+
+// suggestionMarkup wraps formatted text from a suggested insertion or deletion in
+// CriticMarkup ({++added++} or {--removed--}). Surrounding whitespace stays outside
+// the markers. The Docs API doesn't expose who made a suggestion, so no author is shown.
+func suggestionMarkup(formatted string, tr *docs.TextRun) string {
+	var openMark, closeMark string
+	switch {
+	case len(tr.SuggestedDeletionIds) > 0:
+		openMark, closeMark = "{--", "--}"
+	case len(tr.SuggestedInsertionIds) > 0:
+		openMark, closeMark = "{++", "++}"
+	default:
+		return formatted
+	}
+	trimmed := strings.TrimSpace(formatted)
+	if trimmed == "" {
+		return formatted
+	}
+	leadingSpace := formatted[:len(formatted)-len(strings.TrimLeft(formatted, " \t\n"))]
+	trailingSpace := formatted[len(strings.TrimRight(formatted, " \t\n")):]
+	return leadingSpace + openMark + trimmed + closeMark + trailingSpace
+}
+
+// asciiTypography converts smart typography to ASCII equivalents for compatibility.
+func asciiTypography(text string) string {
+	text = strings.ReplaceAll(text, "\u2018", "'")  // left single quote
+	text = strings.ReplaceAll(text, "\u2019", "'")  // right single quote / apostrophe
+	text = strings.ReplaceAll(text, "\u201C", "\"") // left double quote
+	text = strings.ReplaceAll(text, "\u201D", "\"") // right double quote
+	text = strings.ReplaceAll(text, "\u2014", "--") // em dash
+	return text
+}
+
+// monospaceFonts are font families treated as code when no "Mono" or "Code" hint is in the name.
+var monospaceFonts = map[string]bool{
+	"courier": true, "courier new": true, "consolas": true, "inconsolata": true,
+	"cousine": true, "menlo": true, "monaco": true, "lucida console": true,
+}
+
+// isMonospace reports whether a text style uses a monospace font.
+func isMonospace(style *docs.TextStyle) bool {
+	if style == nil || style.WeightedFontFamily == nil {
+		return false
+	}
+	family := strings.ToLower(style.WeightedFontFamily.FontFamily)
+	return monospaceFonts[family] || strings.Contains(family, "mono") || strings.Contains(family, "code")
+}
+
+// longestBacktickRun returns the length of the longest run of backticks in s.
+func longestBacktickRun(s string) int {
+	longest, run := 0, 0
+	for _, r := range s {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
 		}
 	}
+	return longest
+}
 
-	sb.WriteString("\n")
+// inlineCode wraps text in a code span, using a longer delimiter if the text contains backticks.
+func inlineCode(text string) string {
+	delim := strings.Repeat("`", longestBacktickRun(text)+1)
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		text = " " + text + " "
+	}
+	return delim + text + delim
 }
 
-// ListInFolderTool returns the tool definition for listing docs in a folder.
-func (d *DocsTools) ListInFolderTool() mcp.Tool {
-	return mcp.NewTool("docs_list_in_folder",
-		mcp.WithDescription(`Lists Google Docs within a specific Drive folder.
+// isCodeParagraph reports whether a paragraph is a line of a code block: a plain
+// (non-heading, non-list) paragraph made up only of monospace text.
+func isCodeParagraph(para *docs.Paragraph) bool {
+	if para == nil || para.Bullet != nil || len(para.Elements) == 0 {
+		return false
+	}
+	if para.ParagraphStyle != nil && strings.HasPrefix(para.ParagraphStyle.NamedStyleType, "HEADING") {
+		return false
+	}
+	hasText := false
+	for _, e := range para.Elements {
+		if e.TextRun == nil || !isMonospace(e.TextRun.TextStyle) {
+			return false
+		}
+		if strings.TrimSpace(e.TextRun.Content) != "" {
+			hasText = true
+		}
+	}
+	return hasText || len(para.Elements) == 1 && para.Elements[0].TextRun.Content == "\n"
+}
 
-Returns:
-    str: A formatted list of Google Docs in the specified folder.`),
-		mcp.WithString("folder_id",
-			mcp.Description("The folder ID (defaults to 'root' for root folder)"),
-		),
-		mcp.WithNumber("page_size",
-			mcp.Description("Maximum number of results to return (default 100)"),
-			mcp.Min(1),
-			mcp.Max(1000),
-		),
-		mcp.WithString("page_token",
-			mcp.Description("Page token from previous response to continue pagination"),
-		),
-		mcp.WithString("order_by",
-			mcp.Description("Sort order: createdTime, modifiedTime, name, name_natural (append ' desc' for descending)"),
+// codeBlockTable returns the paragraphs of a code block building block, which
+// Docs represents as a single-cell table of monospace text.
+func codeBlockTable(table *docs.Table) ([]*docs.Paragraph, bool) {
+	if table == nil || len(table.TableRows) != 1 || len(table.TableRows[0].TableCells) != 1 {
+		return nil, false
+	}
+	var paras []*docs.Paragraph
+	hasText := false
+	for _, elem := range table.TableRows[0].TableCells[0].Content {
+		if !isCodeParagraph(elem.Paragraph) {
+			return nil, false
+		}
+		paras = append(paras, elem.Paragraph)
+		if strings.TrimSpace(paragraphText(elem.Paragraph)) != "" {
+			hasText = true
+		}
+	}
+	return paras, hasText
+}
+
+// paragraphText returns the raw text of a paragraph's text runs, leaving out
+// text suggested for deletion.
+func paragraphText(para *docs.Paragraph) string {
+	var sb strings.Builder
+	for _, e := range para.Elements {
+		if e.TextRun != nil && len(e.TextRun.SuggestedDeletionIds) == 0 {
+			sb.WriteString(e.TextRun.Content)
+		}
+	}
+	return sb.String()
+}
+
+// shebangLanguages maps interpreters named in a shebang line to fenced code language hints.
+var shebangLanguages = map[string]string{
+	"sh": "sh", "bash": "bash", "zsh": "zsh", "python": "python", "python3": "python",
+	"node": "javascript", "ruby": "ruby", "perl": "perl",
+}
+
+// codeLanguage returns a language hint for a code block, taken from a leading
+// shebang line. Docs doesn't expose the language chosen for code building blocks.
+func codeLanguage(code string) string {
+	first, _, _ := strings.Cut(code, "\n")
+	if !strings.HasPrefix(first, "#!") {
+		return ""
+	}
+	fields := strings.Fields(strings.TrimPrefix(first, "#!"))
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := path.Base(fields[0])
+	if interpreter == "env" && len(fields) > 1 {
+		interpreter = fields[1]
+	}
+	return shebangLanguages[interpreter]
+}
+
+// writeCodeBlock writes paragraphs as a fenced code block. Text is written
+// verbatim; the fence is lengthened if the code itself contains backticks.
+func writeCodeBlock(sb *strings.Builder, paras []*docs.Paragraph) {
+	var code strings.Builder
+	for _, para := range paras {
+		line := strings.ReplaceAll(paragraphText(para), "\u000B", "\n")
+		code.WriteString(strings.TrimSuffix(asciiTypography(line), "\n"))
+		code.WriteString("\n")
+	}
+	text := strings.Trim(code.String(), "\n")
+	if text == "" {
+		return
+	}
+
+	if sb.Len() > 0 && !strings.HasSuffix(sb.String(), "\n\n") {
+		sb.WriteString("\n")
+	}
+	fence := strings.Repeat("`", max(3, longestBacktickRun(text)+1))
+	sb.WriteString(fence)
+	sb.WriteString(codeLanguage(text))
+	sb.WriteString("\n")
+	sb.WriteString(text)
+	sb.WriteString("\n")
+	sb.WriteString(fence)
+	sb.WriteString("\n\n")
+}
+
+// processTable converts a table to markdown. Tables with merged cells or nested
+// tables are written as HTML when opts.HTMLTables is set, since Markdown tables
+// can't represent them; otherwise spanned-over cells are left empty and nested
+// tables are flattened into their cell.
+func processTable(sb *strings.Builder, table *docs.Table, dc *docContext, headingOffset int) {
+	if table == nil || len(table.TableRows) == 0 {
+		return
+	}
+	if dc.tableDepth == 0 {
+		dc.commentIndent = ""
+	}
+	dc.tableDepth++
+	defer func() { dc.tableDepth-- }()
+
+	if dc.opts.HTMLTables && tableNeedsHTML(table) {
+		if sb.Len() > 0 && !strings.HasSuffix(sb.String(), "\n\n") {
+			sb.WriteString("\n")
+		}
+		writeHTMLTable(sb, table, dc, headingOffset)
+		sb.WriteString("\n")
+		return
+	}
+
+	grid := tableGrid(table)
+	columns := 0
+	for _, row := range grid {
+		columns = max(columns, len(row))
+	}
+
+	sb.WriteString("\n")
+
+	writeRow := func(cells []string) {
+		sb.WriteString("|")
+		for _, cell := range cells {
+			sb.WriteString(" ")
+			sb.WriteString(cell)
+			sb.WriteString(" |")
+		}
+		sb.WriteString("\n")
+	}
+	writeSeparator := func() {
+		sb.WriteString("|")
+		for range columns {
+			sb.WriteString(" --- |")
+		}
+		sb.WriteString("\n")
+	}
+
+	// Markdown requires a header row; use an empty one if the table has none
+	if !isHeaderRow(table, 0) {
+		writeRow(make([]string, columns))
+		writeSeparator()
+	}
+	for rowIdx, row := range grid {
+		cells := make([]string, columns)
+		for col, cell := range row {
+			if cell != nil {
+				cells[col] = markdownCellText(cell, dc, headingOffset)
+			}
+		}
+		writeRow(cells)
+		if rowIdx == 0 && isHeaderRow(table, 0) {
+			writeSeparator()
+		}
+	}
+
+	sb.WriteString("\n")
+}
+
+// tableGrid lays out a table's cells by column. Positions covered by a merged
+// cell are nil. Docs normally returns every cell position in a row, including
+// covered ones; if a row is short, its cells are placed in the uncovered positions.
+func tableGrid(table *docs.Table) [][]*docs.TableCell {
+	columns := int(table.Columns)
+	for _, row := range table.TableRows {
+		columns = max(columns, len(row.TableCells))
+	}
+
+	grid := make([][]*docs.TableCell, len(table.TableRows))
+	covered := make([][]bool, len(table.TableRows))
+	for r := range grid {
+		grid[r] = make([]*docs.TableCell, columns)
+		covered[r] = make([]bool, columns)
+	}
+
+	for r, row := range table.TableRows {
+		col := 0
+		for i, cell := range row.TableCells {
+			if len(row.TableCells) == columns {
+				col = i
+			} else {
+				for col < columns && covered[r][col] {
+					col++
+				}
+			}
+			if col >= columns || covered[r][col] {
+				col++
+				continue
+			}
+			grid[r][col] = cell
+
+			rowSpan, colSpan := cellSpans(cell)
+			for dr := 0; dr < rowSpan && r+dr < len(grid); dr++ {
+				for dcol := 0; dcol < colSpan && col+dcol < columns; dcol++ {
+					if dr > 0 || dcol > 0 {
+						covered[r+dr][col+dcol] = true
+					}
+				}
+			}
+			col++
+		}
+	}
+	return grid
+}
+
+// cellSpans returns the number of rows and columns a cell spans (at least 1 each).
+func cellSpans(cell *docs.TableCell) (rowSpan, colSpan int) {
+	rowSpan, colSpan = 1, 1
+	if cell.TableCellStyle != nil {
+		rowSpan = max(rowSpan, int(cell.TableCellStyle.RowSpan))
+		colSpan = max(colSpan, int(cell.TableCellStyle.ColumnSpan))
+	}
+	return rowSpan, colSpan
+}
+
+// tableNeedsHTML reports whether a table has merged cells or nested tables.
+func tableNeedsHTML(table *docs.Table) bool {
+	for _, row := range table.TableRows {
+		for _, cell := range row.TableCells {
+			if rowSpan, colSpan := cellSpans(cell); rowSpan > 1 || colSpan > 1 {
+				return true
+			}
+			for _, elem := range cell.Content {
+				if elem.Table != nil {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isHeaderRow reports whether a row is a header: a row pinned as a table header,
+// or a first row whose text is all bold.
+func isHeaderRow(table *docs.Table, rowIdx int) bool {
+	if rowIdx >= len(table.TableRows) {
+		return false
+	}
+	row := table.TableRows[rowIdx]
+	if row.TableRowStyle != nil && row.TableRowStyle.TableHeader {
+		return true
+	}
+	if rowIdx != 0 {
+		return false
+	}
+	hasText := false
+	for _, cell := range row.TableCells {
+		for _, elem := range cell.Content {
+			if elem.Paragraph == nil {
+				continue
+			}
+			for _, e := range elem.Paragraph.Elements {
+				if e.TextRun == nil || strings.TrimSpace(e.TextRun.Content) == "" {
+					continue
+				}
+				if e.TextRun.TextStyle == nil || !e.TextRun.TextStyle.Bold {
+					return false
+				}
+				hasText = true
+			}
+		}
+	}
+	return hasText
+}
+
+// cellMarkdown converts a cell's content to Markdown. Nested tables are written as
+// HTML when opts.HTMLTables is set, and otherwise flattened to one line per row.
+func cellMarkdown(cell *docs.TableCell, dc *docContext, headingOffset int) string {
+	var sb strings.Builder
+	start := 0
+	flush := func(end int) {
+		if start < end {
+			extractMarkdownContent(&sb, cell.Content[start:end], dc, headingOffset)
+		}
+	}
+	for i, elem := range cell.Content {
+		if elem.Table == nil || dc.opts.HTMLTables {
+			continue
+		}
+		flush(i)
+		start = i + 1
+		for _, row := range tableGrid(elem.Table) {
+			var cells []string
+			for _, c := range row {
+				if c != nil {
+					cells = append(cells, markdownCellText(c, dc, headingOffset))
+				}
+			}
+			sb.WriteString(strings.Join(cells, " | "))
+			sb.WriteString("\n\n")
+		}
+	}
+	flush(len(cell.Content))
+	return normalizeNewlines(sb.String())
+}
+
+// codeFenceRe matches the opening fence of a code block written by writeCodeBlock.
+var codeFenceRe = regexp.MustCompile("^(`{3,})[\\w+-]*$")
+
+// markdownCellText converts a cell to a single line for a Markdown table. Paragraphs
+// and list items are separated by <br>, keeping list markers and indentation.
+// Fenced code can't span table rows, so code blocks are written with cellCode.
+func markdownCellText(cell *docs.TableCell, dc *docContext, headingOffset int) string {
+	var lines, code []string
+	fence := ""
+	for _, line := range strings.Split(cellMarkdown(cell, dc, headingOffset), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if fence != "" {
+			if trimmed == fence {
+				if text := cellCode(code, dc.opts.HTMLTables); text != "" {
+					lines = append(lines, text)
+				}
+				fence, code = "", nil
+			} else {
+				code = append(code, line)
+			}
+			continue
+		}
+		if m := codeFenceRe.FindStringSubmatch(trimmed); m != nil {
+			fence = m[1]
+			continue
+		}
+		if strings.TrimSpace(trimmed) == "" {
+			continue
+		}
+		// Leading spaces are collapsed in table cells, so keep list nesting visible
+		indent := strings.Repeat("&nbsp;", len(line)-len(trimmed))
+		lines = append(lines, indent+strings.TrimRight(trimmed, " "))
+	}
+	return strings.ReplaceAll(strings.Join(lines, "<br>"), "|", `\|`)
+}
+
+// cellCode formats the lines of a code block for a Markdown table cell: as a <pre>
+// element when asHTML is set, and otherwise as one code span per line.
+func cellCode(lines []string, asHTML bool) string {
+	var parts []string
+	for _, line := range lines {
+		switch {
+		case asHTML:
+			parts = append(parts, html.EscapeString(line))
+		case strings.TrimSpace(line) != "":
+			parts = append(parts, inlineCode(line))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	if asHTML {
+		return "<pre>" + strings.Join(parts, "<br>") + "</pre>"
+	}
+	return strings.Join(parts, "<br>")
+}
+
+// writeHTMLTable writes a table as HTML with rowspan/colspan for merged cells.
+// Cell content is written as Markdown surrounded by blank lines so that renderers
+// such as GitHub's still format it, including lists and nested tables.
+func writeHTMLTable(sb *strings.Builder, table *docs.Table, dc *docContext, headingOffset int) {
+	sb.WriteString("<table>\n")
+	for rowIdx, row := range tableGrid(table) {
+		tag := "td"
+		if isHeaderRow(table, rowIdx) {
+			tag = "th"
+		}
+		sb.WriteString("<tr>\n")
+		for _, cell := range row {
+			if cell == nil {
+				continue
+			}
+			sb.WriteString("<" + tag)
+			rowSpan, colSpan := cellSpans(cell)
+			if rowSpan > 1 {
+				fmt.Fprintf(sb, ` rowspan="%d"`, rowSpan)
+			}
+			if colSpan > 1 {
+				fmt.Fprintf(sb, ` colspan="%d"`, colSpan)
+			}
+			sb.WriteString(">")
+			if content := strings.TrimSpace(cellMarkdown(cell, dc, headingOffset)); content != "" {
+				sb.WriteString("\n\n")
+				sb.WriteString(content)
+				sb.WriteString("\n\n")
+			}
+			sb.WriteString("</" + tag + ">\n")
+		}
+		sb.WriteString("</tr>\n")
+	}
+	sb.WriteString("</table>\n")
+}
+
+// DocsGetOutlineRequest contains arguments for getting a document outline.
+type DocsGetOutlineRequest struct {
+	DocumentID string `json:"document_id"`
+}
+
+// DocsGetOutlineResponse contains the heading hierarchy of each tab.
+type DocsGetOutlineResponse struct {
+	DocID    string           `json:"docId"`
+	DocTitle string           `json:"docTitle"`
+	Tabs     []DocsOutlineTab `json:"tabs"`
+}
+
+// DocsOutlineTab is the outline of a single tab.
+type DocsOutlineTab struct {
+	TabID      string                `json:"tabId"`
+	TabTitle   string                `json:"tabTitle"`
+	Characters int                   `json:"characters"` // Approximate text length of the whole tab
+	Words      int                   `json:"words"`
+	Link       string                `json:"link"`
+	Headings   []*DocsOutlineHeading `json:"headings,omitempty"`
+	Warnings   []string              `json:"warnings,omitempty"` // Untrusted content findings (MCP_HARDEN_UNTRUSTED)
+}
+
+// DocsOutlineHeading is a heading and its nested subheadings. Counts cover the
+// whole section, up to the next heading of the same or higher level.
+type DocsOutlineHeading struct {
+	HeadingID  string                `json:"headingId,omitempty"`
+	Text       string                `json:"text"`
+	Level      int                   `json:"level"`
+	Characters int                   `json:"characters"`
+	Words      int                   `json:"words"`
+	Link       string                `json:"link,omitempty"`
+	Children   []*DocsOutlineHeading `json:"children,omitempty"`
+}
+
+// GetOutlineTool returns the tool definition for fetching a document outline.
+func (d *DocsTools) GetOutlineTool() mcp.Tool {
+	return mcp.NewTool("docs_get_outline",
+		mcp.WithDescription(`Retrieves the table of contents of a Google Doc without its content.
+
+Returns each tab's nested heading hierarchy with heading IDs, approximate character and word counts per section, and deep links. Use it before reading a large document, then call docs_get_content with tab_id and heading to fetch specific sections.
+
+Returns a JSON object:
+  - docId: The document ID
+  - docTitle: The document title
+  - tabs: Array of tab objects, each containing:
+    - tabId, tabTitle, characters, words, link
+    - headings: Array of headings with headingId, text, level, characters, words, link, and nested children`),
+		mcp.WithString("document_id",
+			mcp.Required(),
+			mcp.Description("The document ID (from the URL or docs_search results)"),
+		),
+	)
+}
+
+// GetOutlineHandler handles docs_get_outline tool calls.
+func (d *DocsTools) GetOutlineHandler(ctx context.Context, request mcp.CallToolRequest, args DocsGetOutlineRequest) (*mcp.CallToolResult, error) {
+	if args.DocumentID == "" {
+		return mcp.NewToolResultError("document_id is required"), nil
+	}
+
+	if err := d.checkDocumentPolicy(ctx, args.DocumentID); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	doc, err := d.docsService.Documents.Get(args.DocumentID).
+		IncludeTabsContent(true).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return mcp.NewToolResultError("failed to get document: " + err.Error()), nil
+	}
+
+	response := DocsGetOutlineResponse{
+		DocID:    args.DocumentID,
+		DocTitle: doc.Title,
+		Tabs:     []DocsOutlineTab{},
+	}
+
+	if len(doc.Tabs) > 0 {
+		progress := types.NewProgress(ctx, request)
+		total := float64(countTabs(doc.Tabs))
+		err = walkTabs(ctx, doc.Tabs, func(tab *docs.Tab) error {
+			tabTitle := tab.TabProperties.Title
+			if tabTitle == "" {
+				tabTitle = doc.Title
+			}
+			response.Tabs = append(response.Tabs, buildOutline(args.DocumentID, tab.TabProperties.TabId, tabTitle, tab.DocumentTab.Body))
+			progress.Report(float64(len(response.Tabs)), total, "outlined tab "+tabTitle)
+			return nil
+		})
+		if err != nil {
+			return mcp.NewToolResultError("failed to outline document: " + err.Error()), nil
+		}
+	} else if doc.Body != nil {
+		// Fallback for legacy single-tab documents
+		response.Tabs = append(response.Tabs, buildOutline(args.DocumentID, "", doc.Title, doc.Body))
+	}
+
+	data, err := types.MarshalResponse(response)
+	if err != nil {
+		return mcp.NewToolResultError("failed to marshal response: " + err.Error()), nil
+	}
+	return mcp.NewToolResultText(data), nil
+}
+
+// docLink returns a link to a document, optionally to a tab and a heading within it.
+func docLink(documentID, tabID, headingID string) string {
+	link := "https://docs.google.com/document/d/" + documentID + "/edit"
+	if tabID != "" {
+		link += "?tab=" + tabID
+	}
+	if headingID != "" {
+		link += "#heading=" + headingID
+	}
+	return link
+}
+
+// buildOutline builds the heading hierarchy of a tab body, counting the text of
+// each section.
+func buildOutline(documentID, tabID, tabTitle string, body *docs.Body) DocsOutlineTab {
+	outline := DocsOutlineTab{
+		TabID:    tabID,
+		TabTitle: tabTitle,
+		Link:     docLink(documentID, tabID, ""),
+	}
+	if body == nil {
+		return outline
+	}
+
+	// open holds the current heading at each level of nesting
+	var open []*DocsOutlineHeading
+	for _, elem := range body.Content {
+		if level := paragraphHeadingLevel(elem.Paragraph); level > 0 {
+			text := strings.Join(strings.Fields(asciiTypography(paragraphText(elem.Paragraph))), " ")
+			if text == "" {
+				continue
+			}
+			heading := &DocsOutlineHeading{
+				HeadingID: elem.Paragraph.ParagraphStyle.HeadingId,
+				Text:      sanitizeUntrustedField("heading", text, &outline.Warnings),
+				Level:     level,
+			}
+			if heading.HeadingID != "" {
+				heading.Link = docLink(documentID, tabID, heading.HeadingID)
+			}
+
+			for len(open) > 0 && open[len(open)-1].Level >= level {
+				open = open[:len(open)-1]
+			}
+			if len(open) == 0 {
+				outline.Headings = append(outline.Headings, heading)
+			} else {
+				parent := open[len(open)-1]
+				parent.Children = append(parent.Children, heading)
+			}
+			open = append(open, heading)
+		}
+
+		text := elementText(elem)
+		chars, words := len([]rune(strings.TrimSpace(text))), len(strings.Fields(text))
+		outline.Characters += chars
+		outline.Words += words
+		for _, h := range open {
+			h.Characters += chars
+			h.Words += words
+		}
+	}
+	return outline
+}
+
+// elementText returns the plain text of a structural element, including table cells.
+func elementText(elem *docs.StructuralElement) string {
+	switch {
+	case elem.Paragraph != nil:
+		return paragraphText(elem.Paragraph)
+	case elem.Table != nil:
+		var sb strings.Builder
+		for _, row := range elem.Table.TableRows {
+			for _, cell := range row.TableCells {
+				for _, c := range cell.Content {
+					sb.WriteString(elementText(c))
+					sb.WriteString(" ")
+				}
+			}
+		}
+		return sb.String()
+	}
+	return ""
+}
+
+// docsExportFormat describes a Drive export format for docs_export.
+type docsExportFormat struct {
+	mimeType  string
+	extension string
+	binary    bool // Returned as an embedded resource rather than text
+}
+
+// docsExportFormats maps docs_export format names to Drive export formats.
+var docsExportFormats = map[string]docsExportFormat{
+	"text":     {mimeType: "text/plain", extension: ".txt"},
+	"html":     {mimeType: "text/html", extension: ".html"},
+	"markdown": {mimeType: "text/markdown", extension: ".md"},
+	"pdf":      {mimeType: "application/pdf", extension: ".pdf", binary: true},
+	"docx":     {mimeType: docxMimeType, extension: ".docx", binary: true},
+}
+
+// maxExportBytes is the largest export docs_export will read. Drive itself refuses
+// to export more than 10MB.
+const maxExportBytes = 10 << 20
+
+// unsafeFileNameRe matches runs of characters not allowed in saved export file names.
+var unsafeFileNameRe = regexp.MustCompile(`[^\p{L}\p{N} ._-]+`)
+
+// DocsExportRequest contains arguments for exporting a document.
+type DocsExportRequest struct {
+	DocumentID string `json:"document_id"`
+	Format     string `json:"format"` // text, html, markdown, pdf, or docx
+	Save       bool   `json:"save"`   // Save to MCP_EXPORT_DIR instead of returning content
+}
+
+// DocsExportResponse describes an exported document.
+type DocsExportResponse struct {
+	DocID    string   `json:"docId"`
+	DocTitle string   `json:"docTitle"`
+	Format   string   `json:"format"`
+	MimeType string   `json:"mimeType"`
+	Size     int      `json:"size"`               // Exported size in bytes
+	Path     string   `json:"path,omitempty"`     // Local file, when saved
+	Content  string   `json:"content,omitempty"`  // Exported text, for text formats returned inline
+	Warnings []string `json:"warnings,omitempty"` // Untrusted content findings (MCP_HARDEN_UNTRUSTED)
+}
+
+// ExportTool returns the tool definition for exporting a document.
+func (d *DocsTools) ExportTool() mcp.Tool {
+	return mcp.NewTool("docs_export",
+		mcp.WithDescription(`Exports a Google Doc using Google's own converters.
+
+Formats: text, html, markdown (Google's Markdown export, which differs from docs_get_content), pdf, and docx. Text formats are returned inline; pdf and docx are returned as an embedded resource.
+
+With save, the file is written to the server's export directory (MCP_EXPORT_DIR) and only its path is returned. Existing files are never overwritten.`),
+		mcp.WithString("document_id",
+			mcp.Required(),
+			mcp.Description("The document ID (from the URL or search results)"),
+		),
+		mcp.WithString("format",
+			mcp.Required(),
+			mcp.Description("Export format"),
+			mcp.Enum("text", "html", "markdown", "pdf", "docx"),
+		),
+		mcp.WithBoolean("save",
+			mcp.Description("Save to the export directory instead of returning the content (default false)"),
+		),
+	)
+}
+
+// ExportHandler handles docs_export tool calls.
+func (d *DocsTools) ExportHandler(ctx context.Context, request mcp.CallToolRequest, args DocsExportRequest) (*mcp.CallToolResult, error) {
+	if args.DocumentID == "" {
+		return mcp.NewToolResultError("document_id is required"), nil
+	}
+	format, ok := docsExportFormats[args.Format]
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid format %q: must be text, html, markdown, pdf, or docx", args.Format)), nil
+	}
+	if args.Save && types.GlobalExportDir == "" {
+		return mcp.NewToolResultError("save is disabled: set MCP_EXPORT_DIR to enable it"), nil
+	}
+
+	f, err := d.driveService.Files.Get(args.DocumentID).
+		Context(ctx).
+		Fields("id, name, mimeType, " + docsPolicyFields).
+		SupportsAllDrives(true).
+		Do()
+	if err != nil {
+		return mcp.NewToolResultError("failed to get document metadata: " + err.Error()), nil
+	}
+	if f.MimeType != docsMimeType {
+		return mcp.NewToolResultError(fmt.Sprintf("%s is not a Google Doc (type %s)", args.DocumentID, f.MimeType)), nil
+	}
+	if err := d.checkFilePolicy(ctx, f); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	resp, err := d.driveService.Files.Export(f.Id, format.mimeType).Context(ctx).Download()
+	if err != nil {
+		return mcp.NewToolResultError("failed to export document: " + err.Error()), nil
+	}
+	data, err := readLimited(resp.Body, maxExportBytes)
+	resp.Body.Close()
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	response := DocsExportResponse{
+		DocID:    f.Id,
+		DocTitle: f.Name,
+		Format:   args.Format,
+		MimeType: format.mimeType,
+		Size:     len(data),
+	}
+
+	if args.Save {
+		response.Path, err = saveExport(f.Name, format.extension, data)
+		if err != nil {
+			return mcp.NewToolResultError("failed to save export: " + err.Error()), nil
+		}
+	} else if !format.binary {
+		response.Content = wrapUntrusted("docs:"+f.Id, normalizeNewlines(string(data)), &response.Warnings)
+	}
+
+	text, err := types.MarshalResponse(response)
+	if err != nil {
+		return mcp.NewToolResultError("failed to marshal response: " + err.Error()), nil
+	}
+	if args.Save || !format.binary {
+		return mcp.NewToolResultText(text), nil
+	}
+	return mcp.NewToolResultResource(text, mcp.BlobResourceContents{
+		URI:      fmt.Sprintf("https://docs.google.com/document/d/%s/export?format=%s", f.Id, args.Format),
+		MIMEType: format.mimeType,
+		Blob:     base64.StdEncoding.EncodeToString(data),
+	}), nil
+}
+
+// saveExport writes data to a new file in the export directory, named after the
+// document title. A numeric suffix is added rather than overwriting an existing file.
+func saveExport(title, extension string, data []byte) (string, error) {
+	root, err := os.OpenRoot(types.GlobalExportDir)
+	if err != nil {
+		return "", err
+	}
+	defer root.Close()
+
+	base := exportFileName(title)
+	for i := 0; i < 100; i++ {
+		name := base + extension
+		if i > 0 {
+			name = fmt.Sprintf("%s (%d)%s", base, i, extension)
+		}
+		f, err := root.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if _, err := f.Write(data); err != nil {
+			f.Close()
+			return "", err
+		}
+		if err := f.Close(); err != nil {
+			return "", err
+		}
+		return filepath.Join(types.GlobalExportDir, name), nil
+	}
+	return "", fmt.Errorf("too many existing files named %q", base+extension)
+}
+
+// exportFileName turns a document title into a safe file name without an extension.
+func exportFileName(title string) string {
+	name := strings.Trim(unsafeFileNameRe.ReplaceAllString(title, "_"), " ._")
+	if utf8.RuneCountInString(name) > 100 {
+		name = strings.TrimRight(string([]rune(name)[:100]), " ._")
+	}
+	if name == "" {
+		name = "document"
+	}
+	return name
+}
+
+// MarshalCompact returns a compact text representation of the export.
+func (d DocsExportResponse) MarshalCompact() string {
+	var sb strings.Builder
+	sb.WriteString("=== Export: ")
+	sb.WriteString(d.DocTitle)
+	sb.WriteString(" ===\nID: ")
+	sb.WriteString(d.DocID)
+	fmt.Fprintf(&sb, "\nFormat: %s (%s, %d bytes)\n", d.Format, d.MimeType, d.Size)
+	if d.Path != "" {
+		sb.WriteString("Saved to: ")
+		sb.WriteString(d.Path)
+		sb.WriteString("\n")
+	}
+	for _, w := range d.Warnings {
+		sb.WriteString("Warning: ")
+		sb.WriteString(w)
+		sb.WriteString("\n")
+	}
+	if d.Content != "" {
+		sb.WriteString("\n")
+		sb.WriteString(d.Content)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// docsRevisionFields are the Drive revision fields returned by docs_list_revisions.
+const docsRevisionFields = "id, modifiedTime, keepForever, lastModifyingUser(displayName, emailAddress, me)"
+
+// defaultDiffContextLines is the default number of unchanged lines around unified diff hunks.
+const defaultDiffContextLines = 3
+
+// DocsListRevisionsRequest contains arguments for listing document revisions.
+type DocsListRevisionsRequest struct {
+	DocumentID string `json:"document_id"`
+	PageSize   int    `json:"page_size"`
+	PageToken  string `json:"page_token"`
+	FetchAll   bool   `json:"fetch_all"` // Follow page tokens and merge all pages
+	MaxItems   int    `json:"max_items"` // Cap on total results when FetchAll is set
+}
+
+// DocsRevision represents a saved revision of a document.
+type DocsRevision struct {
+	ID           string `json:"id"`
+	ModifiedTime string `json:"modified_time"`
+	Author       string `json:"author,omitempty"`
+	AuthorIsMe   bool   `json:"author_is_me"`
+	KeepForever  bool   `json:"keep_forever,omitempty"`
+}
+
+// DocsListRevisionsResponse contains a document's revisions, oldest first.
+type DocsListRevisionsResponse struct {
+	DocumentID    string         `json:"document_id"`
+	Revisions     []DocsRevision `json:"revisions"`
+	NextPageToken string         `json:"next_page_token,omitempty"`
+	Truncated     bool           `json:"truncated,omitempty"` // fetch_all stopped at max_items
+}
+
+// DocsDiffRequest contains arguments for diffing two versions of a document.
+type DocsDiffRequest struct {
+	DocumentID   string `json:"document_id"`
+	FromRevision string `json:"from_revision"` // Old revision ID
+	Since        string `json:"since"`         // RFC3339 time; the old version is the last revision at or before it
+	ToRevision   string `json:"to_revision"`   // New revision ID (default: latest revision)
+	Mode         string `json:"mode"`          // unified or sections
+	ContextLines *int   `json:"context_lines"` // Unchanged lines around unified diff hunks
+}
+
+// DocsDiffResponse contains the differences between two versions of a document.
+type DocsDiffResponse struct {
+	DocumentID string   `json:"document_id"`
+	DocTitle   string   `json:"doc_title,omitempty"`
+	From       string   `json:"from"` // Description of the old version
+	To         string   `json:"to"`   // Description of the new version
+	Mode       string   `json:"mode"`
+	Added      int      `json:"added"`   // Added lines
+	Removed    int      `json:"removed"` // Removed lines
+	Diff       string   `json:"diff,omitempty"`
+	Note       string   `json:"note,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"` // Untrusted content findings (MCP_HARDEN_UNTRUSTED)
+}
+
+// Limits on the document snapshots kept for docs_diff. The least recently used
+// snapshots are dropped first.
+const (
+	maxSnapshots     = 50
+	maxSnapshotBytes = 32 << 20
+)
+
+// docSnapshot is the Markdown of a whole document as last seen by this server.
+type docSnapshot struct {
+	documentID string
+	markdown   string
+	revisionID string
+	taken      time.Time
+}
+
+// ListRevisionsTool returns the tool definition for listing document revisions.
+func (d *DocsTools) ListRevisionsTool() mcp.Tool {
+	return mcp.NewTool("docs_list_revisions",
+		mcp.WithDescription(`Lists the saved revisions of a Google Doc, oldest first, with their authors and times.
+
+Use the revision IDs with docs_diff. Google merges older revisions, so the history is not a record of every edit.`),
+		mcp.WithString("document_id",
+			mcp.Required(),
+			mcp.Description("The document ID"),
+		),
+		mcp.WithNumber("page_size",
+			mcp.Description("Maximum number of revisions per page (default 100, max 1000)"),
+			mcp.Min(1),
+			mcp.Max(1000),
+		),
+		mcp.WithString("page_token",
+			mcp.Description("Page token from previous response to continue pagination"),
+		),
+		withFetchAll(),
+	)
+}
+
+// ListRevisionsHandler handles docs_list_revisions tool calls.
+func (d *DocsTools) ListRevisionsHandler(ctx context.Context, request mcp.CallToolRequest, args DocsListRevisionsRequest) (*mcp.CallToolResult, error) {
+	if args.DocumentID == "" {
+		return mcp.NewToolResultError("document_id is required"), nil
+	}
+
+	if err := d.checkDocumentPolicy(ctx, args.DocumentID); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	pageSize := args.PageSize
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+	pageSize = min(pageSize, 1000)
+
+	fetch := func(ctx context.Context, pageToken string, pageSize int) ([]DocsRevision, string, error) {
+		revisions, next, err := d.listRevisions(ctx, args.DocumentID, docsRevisionFields, pageToken, pageSize)
+		if err != nil {
+			return nil, "", err
+		}
+		var result []DocsRevision
+		for _, r := range revisions {
+			result = append(result, convertRevision(r))
+		}
+		return result, next, nil
+	}
+
+	response := DocsListRevisionsResponse{
+		DocumentID: args.DocumentID,
+	}
+	if args.FetchAll {
+		paged, err := fetchAllPages(ctx, args.PageToken, pageSize, args.MaxItems, types.NewProgress(ctx, request), fetch)
+		if err != nil {
+			return mcp.NewToolResultError("failed to list revisions: " + err.Error()), nil
+		}
+		response.Revisions = paged.Items
+		response.NextPageToken = paged.NextPageToken
+		response.Truncated = paged.Truncated
+	} else {
+		revisions, next, err := fetch(ctx, args.PageToken, pageSize)
+		if err != nil {
+			return mcp.NewToolResultError("failed to list revisions: " + err.Error()), nil
+		}
+		response.Revisions = revisions
+		response.NextPageToken = next
+	}
+
+	data, err := types.MarshalResponse(response)
+	if err != nil {
+		return mcp.NewToolResultError("failed to marshal response: " + err.Error()), nil
+	}
+	return mcp.NewToolResultText(data), nil
+}
+
+// listRevisions fetches one page of a document's revisions with the given fields.
+func (d *DocsTools) listRevisions(ctx context.Context, documentID, fields, pageToken string, pageSize int) ([]*drive.Revision, string, error) {
+	call := d.driveService.Revisions.List(documentID).
+		Context(ctx).
+		Fields(googleapi.Field("nextPageToken, revisions(" + fields + ")")).
+		PageSize(int64(pageSize))
+	if pageToken != "" {
+		call = call.PageToken(pageToken)
+	}
+	list, err := call.Do()
+	if err != nil {
+		return nil, "", err
+	}
+	return list.Revisions, list.NextPageToken, nil
+}
+
+// convertRevision converts a Drive revision to a DocsRevision.
+func convertRevision(r *drive.Revision) DocsRevision {
+	revision := DocsRevision{
+		ID:           r.Id,
+		ModifiedTime: r.ModifiedTime,
+		KeepForever:  r.KeepForever,
+	}
+	if r.LastModifyingUser != nil {
+		revision.Author = formatDriveUser(r.LastModifyingUser)
+		revision.AuthorIsMe = r.LastModifyingUser.Me
+	}
+	return revision
+}
+
+// DiffTool returns the tool definition for diffing document versions.
+func (d *DocsTools) DiffTool() mcp.Tool {
+	return mcp.NewTool("docs_diff",
+		mcp.WithDescription(`Shows what changed in a Google Doc, as a diff of its Markdown.
+
+Compare two saved revisions with from_revision (or since, to start from the last revision at or before a time) and optionally to_revision (default: the latest revision). Revisions are compared using Google's Markdown export.
+
+Without from_revision or since, compares the document with the version this server last returned from docs_get_content or docs_diff, using the same Markdown as docs_get_content, and then remembers the current version. The first call for a document only records it.
+
+mode "unified" returns a unified diff; "sections" groups changed lines by tab and heading.`),
+		mcp.WithString("document_id",
+			mcp.Required(),
+			mcp.Description("The document ID"),
+		),
+		mcp.WithString("from_revision",
+			mcp.Description("Revision ID of the old version (from docs_list_revisions)"),
+		),
+		mcp.WithString("since",
+			mcp.Description("Use the last revision at or before this time as the old version (RFC3339 format)"),
+		),
+		mcp.WithString("to_revision",
+			mcp.Description("Revision ID of the new version (default: the latest revision)"),
+		),
+		mcp.WithString("mode",
+			mcp.Description("Diff format (default unified)"),
+			mcp.Enum("unified", "sections"),
+		),
+		mcp.WithNumber("context_lines",
+			mcp.Description(fmt.Sprintf("Unchanged lines shown around changes in unified mode (default %d)", defaultDiffContextLines)),
+			mcp.Min(0),
+			mcp.Max(20),
+		),
+	)
+}
+
+// DiffHandler handles docs_diff tool calls.
+func (d *DocsTools) DiffHandler(ctx context.Context, request mcp.CallToolRequest, args DocsDiffRequest) (*mcp.CallToolResult, error) {
+	if args.DocumentID == "" {
+		return mcp.NewToolResultError("document_id is required"), nil
+	}
+	mode := args.Mode
+	if mode == "" {
+		mode = "unified"
+	}
+	if mode != "unified" && mode != "sections" {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid mode %q: must be unified or sections", args.Mode)), nil
+	}
+	if args.FromRevision != "" && args.Since != "" {
+		return mcp.NewToolResultError("from_revision and since cannot be used together"), nil
+	}
+	if args.ToRevision != "" && args.FromRevision == "" && args.Since == "" {
+		return mcp.NewToolResultError("to_revision requires from_revision or since"), nil
+	}
+	var since time.Time
+	if args.Since != "" {
+		var err error
+		since, err = time.Parse(time.RFC3339, args.Since)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid since %q: must be an RFC3339 timestamp such as 2025-01-01T00:00:00Z", args.Since)), nil
+		}
+	}
+	contextLines := defaultDiffContextLines
+	if args.ContextLines != nil {
+		contextLines = min(max(*args.ContextLines, 0), 20)
+	}
+
+	if err := d.checkDocumentPolicy(ctx, args.DocumentID); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	response := DocsDiffResponse{
+		DocumentID: args.DocumentID,
+		Mode:       mode,
+	}
+	var oldText, newText string
+	var err error
+	if args.FromRevision != "" || args.Since != "" {
+		oldText, newText, err = d.revisionTexts(ctx, args, since, &response)
+	} else {
+		oldText, newText, err = d.snapshotTexts(ctx, request, &response)
+	}
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	ops := diffLines(splitLines(normalizeNewlines(oldText)), splitLines(normalizeNewlines(newText)))
+	stats := countChanges(ops)
+	response.Added = stats.added
+	response.Removed = stats.removed
+	if mode == "sections" {
+		response.Diff = sectionDiff(ops)
+	} else {
+		response.Diff = unifiedDiff(ops, response.From, response.To, contextLines)
+	}
+	if response.Diff == "" && response.From != response.To {
+		response.Note = strings.TrimSpace(response.Note + " No changes.")
+	}
+
+	if response.Diff != "" {
+		response.Diff = wrapUntrusted("docs:"+args.DocumentID, response.Diff, &response.Warnings)
+	}
+
+	data, err := types.MarshalResponse(response)
+	if err != nil {
+		return mcp.NewToolResultError("failed to marshal response: " + err.Error()), nil
+	}
+	return mcp.NewToolResultText(data), nil
+}
+
+// revisionTexts exports the two revisions selected by args, preferring Markdown and
+// falling back to plain text when a revision can't be exported as Markdown.
+func (d *DocsTools) revisionTexts(ctx context.Context, args DocsDiffRequest, since time.Time, response *DocsDiffResponse) (string, string, error) {
+	var revisions []*drive.Revision
+	pageToken := ""
+	for {
+		if err := types.GlobalRateLimiter.Wait(ctx); err != nil {
+			return "", "", err
+		}
+		page, next, err := d.listRevisions(ctx, args.DocumentID, docsRevisionFields+", exportLinks", pageToken, 1000)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to list revisions: %w", err)
+		}
+		revisions = append(revisions, page...)
+		if next == "" {
+			break
+		}
+		pageToken = next
+	}
+	if len(revisions) == 0 {
+		return "", "", fmt.Errorf("document %s has no revisions", args.DocumentID)
+	}
+
+	var from, to *drive.Revision
+	for _, r := range revisions {
+		switch {
+		case args.FromRevision != "" && r.Id == args.FromRevision:
+			from = r
+		case args.Since != "":
+			// Revisions are listed oldest first
+			if t, err := time.Parse(time.RFC3339, r.ModifiedTime); err == nil && !t.After(since) {
+				from = r
+			}
+		}
+		if args.ToRevision != "" && r.Id == args.ToRevision {
+			to = r
+		}
+	}
+	if args.ToRevision == "" {
+		to = revisions[len(revisions)-1]
+	}
+	switch {
+	case from == nil && args.Since != "":
+		return "", "", fmt.Errorf("no revision at or before %s; the oldest is from %s", args.Since, revisions[0].ModifiedTime)
+	case from == nil:
+		return "", "", fmt.Errorf("revision %q not found", args.FromRevision)
+	case to == nil:
+		return "", "", fmt.Errorf("revision %q not found", args.ToRevision)
+	}
+
+	format := "text/markdown"
+	if from.ExportLinks[format] == "" || to.ExportLinks[format] == "" {
+		format = "text/plain"
+	}
+	if from.ExportLinks[format] == "" || to.ExportLinks[format] == "" {
+		return "", "", fmt.Errorf("revisions of this file can't be exported as text")
+	}
+
+	response.From = describeRevision(from)
+	response.To = describeRevision(to)
+	if format != "text/markdown" {
+		response.Note = "Compared as plain text because a revision has no Markdown export."
+	}
+
+	oldText, err := d.downloadExport(ctx, from.ExportLinks[format])
+	if err != nil {
+		return "", "", fmt.Errorf("failed to export revision %s: %w", from.Id, err)
+	}
+	newText, err := d.downloadExport(ctx, to.ExportLinks[format])
+	if err != nil {
+		return "", "", fmt.Errorf("failed to export revision %s: %w", to.Id, err)
+	}
+	return oldText, newText, nil
+}
+
+// describeRevision describes a revision for diff headers, e.g. "revision 42 (2025-01-01T00:00:00Z by Ann)".
+func describeRevision(r *drive.Revision) string {
+	desc := "revision " + r.Id + " (" + r.ModifiedTime
+	if r.LastModifyingUser != nil {
+		desc += " by " + formatDriveUser(r.LastModifyingUser)
+	}
+	return desc + ")"
+}
+
+// downloadExport fetches a Drive export link as text.
+func (d *DocsTools) downloadExport(ctx context.Context, uri string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download failed: %s", resp.Status)
+	}
+	data, err := readLimited(resp.Body, maxExportBytes)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// snapshotTexts converts the current document and returns it along with the cached
+// snapshot, then replaces the snapshot. Without a snapshot, both texts are the
+// current version and response.Note explains that it was recorded.
+func (d *DocsTools) snapshotTexts(ctx context.Context, request mcp.CallToolRequest, response *DocsDiffResponse) (string, string, error) {
+	doc, err := d.docsService.Documents.Get(response.DocumentID).
+		IncludeTabsContent(true).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get document: %w", err)
+	}
+
+	var tabs []DocsTabContent
+	if len(doc.Tabs) > 0 {
+		tabs, err = collectAllTabs(ctx, doc.Tabs, doc.Title, markdownOptions{}, nil, types.NewProgress(ctx, request))
+		if err != nil {
+			return "", "", fmt.Errorf("failed to convert document: %w", err)
+		}
+	} else if doc.Body != nil {
+		markdown, _ := convertDocumentTab(legacyDocumentTab(doc), markdownOptions{})
+		tabs = append(tabs, DocsTabContent{TabTitle: doc.Title, TabMarkdown: markdown})
+	}
+	current := snapshotMarkdown(tabs)
+
+	response.DocTitle = doc.Title
+	response.To = "current version (revision " + doc.RevisionId + ")"
+	previous, ok := d.swapSnapshot(response.DocumentID, docSnapshot{
+		markdown:   current,
+		revisionID: doc.RevisionId,
+		taken:      time.Now(),
+	})
+	if !ok {
+		response.From = response.To
+		response.Note = "No earlier version of this document was seen by this server; the current version has been recorded. Call docs_diff again later to see what changed, or pass from_revision or since."
+		return current, current, nil
+	}
+	response.From = "version seen at " + previous.taken.UTC().Format(time.RFC3339) + " (revision " + previous.revisionID + ")"
+	return previous.markdown, current, nil
+}
+
+// snapshotMarkdown joins converted tabs into a single Markdown text for diffing,
+// with a marker line before each tab.
+func snapshotMarkdown(tabs []DocsTabContent) string {
+	var sb strings.Builder
+	for _, tab := range tabs {
+		fmt.Fprintf(&sb, "<!-- tab: %s -->\n\n", strings.ReplaceAll(tab.TabTitle, "\n", " "))
+		sb.WriteString(strings.TrimSpace(tab.TabMarkdown))
+		sb.WriteString("\n\n")
+	}
+	return sb.String()
+}
+
+// swapSnapshot stores a document snapshot and returns the previous one, if any,
+// then drops the least recently used snapshots beyond the cache limits.
+func (d *DocsTools) swapSnapshot(documentID string, snapshot docSnapshot) (docSnapshot, bool) {
+	d.snapshotMu.Lock()
+	defer d.snapshotMu.Unlock()
+
+	snapshot.documentID = documentID
+	var previous docSnapshot
+	elem, ok := d.snapshots[documentID]
+	if ok {
+		previous = elem.Value.(docSnapshot)
+		d.snapshotBytes -= len(previous.markdown)
+		elem.Value = snapshot
+		d.snapshotOrder.MoveToFront(elem)
+	} else {
+		d.snapshots[documentID] = d.snapshotOrder.PushFront(snapshot)
+	}
+	d.snapshotBytes += len(snapshot.markdown)
+
+	for d.snapshotOrder.Len() > maxSnapshots || d.snapshotBytes > maxSnapshotBytes {
+		oldest := d.snapshotOrder.Remove(d.snapshotOrder.Back()).(docSnapshot)
+		delete(d.snapshots, oldest.documentID)
+		d.snapshotBytes -= len(oldest.markdown)
+	}
+	return previous, ok
+}
+
+// ListInFolderTool returns the tool definition for listing docs in a folder.
+func (d *DocsTools) ListInFolderTool() mcp.Tool {
+	return mcp.NewTool("docs_list_in_folder",
+		mcp.WithDescription(`Lists Google Docs within a specific Drive folder.
+
+Returns:
+    str: A formatted list of Google Docs in the specified folder.`),
+		mcp.WithString("folder_id",
+			mcp.Description("The folder ID (defaults to 'root' for root folder)"),
+		),
+		mcp.WithNumber("page_size",
+			mcp.Description("Maximum number of results to return (default 100)"),
+			mcp.Min(1),
+			mcp.Max(1000),
+		),
+		mcp.WithString("page_token",
+			mcp.Description("Page token from previous response to continue pagination"),
+		),
+		mcp.WithString("order_by",
+			mcp.Description("Sort order: createdTime, modifiedTime, name, name_natural (append ' desc' for descending)"),
+		),
+		mcp.WithString("modified_after",
+			mcp.Description("Only include docs modified after this date (RFC3339 format)"),
+		),
+		mcp.WithString("modified_before",
+			mcp.Description("Only include docs modified before this date (RFC3339 format)"),
+		),
+		withDriveQuery(false),
+		withFetchAll(),
+	)
+}
+
+// ListInFolderHandler handles docs_list_in_folder tool calls.
+func (d *DocsTools) ListInFolderHandler(ctx context.Context, request mcp.CallToolRequest, args DocsListInFolderRequest) (*mcp.CallToolResult, error) {
+	folderID := args.FolderID
+	if folderID == "" {
+		folderID = "root"
+	}
+
+	pageSize := args.PageSize
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+
+	if err := validateDriveID(folderID, "folder_id"); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Build query: docs in folder, exclude trashed
+	q := &driveQuery{}
+	q.in(folderID, "parents").equals("mimeType", docsMimeType).boolean("trashed", false)
+
+	// Add date filters
+	if err := q.timeAfter("modifiedTime", args.ModifiedAfter, "modified_after"); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := q.timeBefore("modifiedTime", args.ModifiedBefore, "modified_before"); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := args.DriveQuery.apply(q, false); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	response, err := d.listDocs(ctx, request, q.String(), args.OrderBy, args.PageToken, pageSize, args.FetchAll, args.MaxItems)
+	if err != nil {
+		return mcp.NewToolResultError("failed to list documents: " + err.Error()), nil
+	}
+
+	data, err := types.MarshalResponse(response)
+	if err != nil {
+		return mcp.NewToolResultError("failed to marshal response: " + err.Error()), nil
+	}
+	return mcp.NewToolResultText(data), nil
+}
+
+// GetCommentsTool returns the tool definition for fetching document comments.
+func (d *DocsTools) GetCommentsTool() mcp.Tool {
+	return mcp.NewTool("docs_get_comments",
+		mcp.WithDescription(`Retrieves comments and replies from a Google Doc.`),
+		mcp.WithString("document_id",
+			mcp.Required(),
+			mcp.Description("The document ID"),
+		),
+		mcp.WithBoolean("include_resolved",
+			mcp.Description("Include resolved comments (default false, only shows open comments)"),
+		),
+		mcp.WithString("page_token",
+			mcp.Description("Page token from previous response to continue pagination"),
+		),
+		mcp.WithNumber("page_size",
+			mcp.Description("Maximum number of comments per page (default 100). Filtered pages can hold fewer if the page's Drive request limit is reached first"),
+			mcp.Min(1),
+			mcp.Max(100),
+		),
+		mcp.WithString("modified_after",
+			mcp.Description("Only include comments modified after this date (RFC3339 format)"),
+		),
+		mcp.WithBoolean("include_location",
+			mcp.Description("Find each comment's quoted text in the document and report its tab, nearest heading, and a link (default false)"),
+		),
+		mcp.WithString("author",
+			mcp.Description("Only include comments started by this email address or display name (case-insensitive). Email addresses are only visible for some authors"),
+		),
+		mcp.WithString("assignee",
+			mcp.Description(`Only include action items assigned to this email address, or "me"`),
+		),
+		mcp.WithBoolean("mentions_me",
+			mcp.Description("Only include comments where you are mentioned in the comment or a reply (default false)"),
+		),
+		mcp.WithBoolean("awaiting_my_reply",
+			mcp.Description("Only include open comments you started, replied to, were mentioned in, or are assigned, where someone else wrote last (default false)"),
+		),
+		withFetchAll(),
+	)
+}
+
+// docsCommentFields are the Drive comment fields used by convertComment.
+const docsCommentFields = "id, author, content, quotedFileContent, createdTime, modifiedTime, resolved, assigneeEmailAddress, mentionedEmailAddresses, replies"
+
+// DocsComment represents a comment on a document.
+type DocsComment struct {
+	ID           string               `json:"id"`
+	Author       string               `json:"author"`
+	AuthorIsMe   bool                 `json:"author_is_me"`
+	AuthorEmail  string               `json:"author_email,omitempty"` // Only when visible to the user
+	Content      string               `json:"content"`
+	Assignee     string               `json:"assignee,omitempty"` // Email address of the action item assignee
+	Mentions     []string             `json:"mentions,omitempty"` // Email addresses mentioned in the content
+	QuotedText   string               `json:"quoted_text,omitempty"`
+	Location     *DocsCommentLocation `json:"location,omitempty"` // Where QuotedText is, with include_location
+	CreatedTime  string               `json:"created_time"`
+	ModifiedTime string               `json:"modified_time,omitempty"`
+	Resolved     bool                 `json:"resolved"`
+	Replies      []DocsCommentReply   `json:"replies,omitempty"`
+	Warnings     []string             `json:"warnings,omitempty"` // Untrusted content findings (MCP_HARDEN_UNTRUSTED)
+
+	quoteKey string // Normalized first line of QuotedText, for locating it
+}
+
+// DocsCommentReply represents a reply to a comment.
+type DocsCommentReply struct {
+	ID          string   `json:"id"`
+	Author      string   `json:"author"`
+	AuthorIsMe  bool     `json:"author_is_me"`
+	AuthorEmail string   `json:"author_email,omitempty"` // Only when visible to the user
+	Content     string   `json:"content"`
+	Mentions    []string `json:"mentions,omitempty"` // Email addresses mentioned in the content
+	CreatedTime string   `json:"created_time"`
+}
+
+// DocsGetCommentsResponse contains the comments for a document.
+type DocsGetCommentsResponse struct {
+	DocumentID    string        `json:"document_id"`
+	Comments      []DocsComment `json:"comments"`
+	NextPageToken string        `json:"next_page_token,omitempty"`
+	Truncated     bool          `json:"truncated,omitempty"` // fetch_all stopped at max_items
+}
+
+// GetCommentsHandler handles docs_get_comments tool calls.
+func (d *DocsTools) GetCommentsHandler(ctx context.Context, request mcp.CallToolRequest, args DocsGetCommentsRequest) (*mcp.CallToolResult, error) {
+	if args.DocumentID == "" {
+		return mcp.NewToolResultError("document_id is required"), nil
+	}
+
+	if err := d.checkDocumentPolicy(ctx, args.DocumentID); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	pageSize := args.PageSize
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+
+	filter := commentFilter{
+		includeResolved: args.IncludeResolved,
+		author:          strings.ToLower(strings.TrimSpace(args.Author)),
+		assignee:        strings.ToLower(strings.TrimSpace(args.Assignee)),
+		mentionsMe:      args.MentionsMe,
+		awaitingMyReply: args.AwaitingMyReply,
+	}
+	if filter.mentionsMe || filter.awaitingMyReply || filter.assignee == "me" {
+		me, err := d.myEmail(ctx)
+		if err != nil {
+			return mcp.NewToolResultError("failed to get your email address: " + err.Error()), nil
+		}
+		filter.me = strings.ToLower(me)
+		if filter.assignee == "me" {
+			filter.assignee = filter.me
+		}
+	}
+
+	// Comments are filtered after listing, so keep reading until the page is full or
+	// maxCommentListRequests requests have been made. Each request asks for no more
+	// comments than are still needed, so a page never holds more than pageSize
+	// comments and the next page token never skips any.
+	fetch := func(ctx context.Context, pageToken string, pageSize int) ([]DocsComment, string, error) {
+		var comments []DocsComment
+		for requests := 0; ; requests++ {
+			if requests > 0 {
+				if err := types.GlobalRateLimiter.Wait(ctx); err != nil {
+					return nil, "", err
+				}
+			}
+			call := d.driveService.Comments.List(args.DocumentID).
+				Context(ctx).
+				Fields("nextPageToken, comments(" + docsCommentFields + ")").
+				IncludeDeleted(false).
+				PageSize(int64(min(pageSize-len(comments), maxCommentListSize)))
+
+			// Apply pagination
+			if pageToken != "" {
+				call = call.PageToken(pageToken)
+			}
+			// Apply modified after filter (API supports startModifiedTime)
+			if args.ModifiedAfter != "" {
+				call = call.StartModifiedTime(args.ModifiedAfter)
+			}
+
+			commentList, err := call.Do()
+			if err != nil {
+				return nil, "", err
+			}
+
+			for _, c := range commentList.Comments {
+				if filter.match(c) {
+					comments = append(comments, convertComment(c))
+				}
+			}
+			pageToken = commentList.NextPageToken
+			if pageToken == "" || len(comments) >= pageSize || requests+1 >= maxCommentListRequests {
+				return comments, pageToken, nil
+			}
+		}
+	}
+
+	response := DocsGetCommentsResponse{
+		DocumentID: args.DocumentID,
+	}
+	if args.FetchAll {
+		paged, err := fetchAllPages(ctx, args.PageToken, pageSize, args.MaxItems, types.NewProgress(ctx, request), fetch)
+		if err != nil {
+			return mcp.NewToolResultError("failed to get comments: " + err.Error()), nil
+		}
+		response.Comments = paged.Items
+		response.NextPageToken = paged.NextPageToken
+		response.Truncated = paged.Truncated
+	} else {
+		comments, next, err := fetch(ctx, args.PageToken, pageSize)
+		if err != nil {
+			return mcp.NewToolResultError("failed to get comments: " + err.Error()), nil
+		}
+		response.Comments = comments
+		response.NextPageToken = next
+	}
+
+	if args.IncludeLocation && len(response.Comments) > 0 {
+		if err := d.locateComments(ctx, args.DocumentID, response.Comments); err != nil {
+			return mcp.NewToolResultError("failed to locate comments: " + err.Error()), nil
+		}
+	}
+
+	data, err := types.MarshalResponse(response)
+	if err != nil {
+		return mcp.NewToolResultError("failed to marshal response: " + err.Error()), nil
+	}
+	return mcp.NewToolResultText(data), nil
+}
+
+const (
+	// maxCommentListSize is the largest page of comments Drive returns.
+	maxCommentListSize = 100
+	// maxCommentListRequests caps the Drive requests made for one page of filtered
+	// comments, so a long run of comments that don't match can't stall a call.
+	maxCommentListRequests = 10
+)
+
+// commentFilter selects comments for docs_get_comments.
+type commentFilter struct {
+	includeResolved bool
+	author          string // Lowercase email address or display name
+	assignee        string // Lowercase email address
+	mentionsMe      bool
+	awaitingMyReply bool
+	me              string // The user's lowercase email address
+}
+
+// match reports whether a comment passes the filter.
+func (f commentFilter) match(c *drive.Comment) bool {
+	if c.Resolved && (!f.includeResolved || f.awaitingMyReply) {
+		return false
+	}
+	if f.author != "" {
+		if c.Author == nil || (!strings.EqualFold(c.Author.EmailAddress, f.author) && !strings.EqualFold(c.Author.DisplayName, f.author)) {
+			return false
+		}
+	}
+	if f.assignee != "" && !strings.EqualFold(c.AssigneeEmailAddress, f.assignee) {
+		return false
+	}
+	if f.mentionsMe && !commentMentions(c, f.me) {
+		return false
+	}
+	if f.awaitingMyReply && !awaitingReply(c, f.me) {
+		return false
+	}
+	return true
+}
+
+// commentMentions reports whether email is mentioned in a comment or any of its replies.
+func commentMentions(c *drive.Comment, email string) bool {
+	mentioned := func(addresses []string) bool {
+		for _, a := range addresses {
+			if strings.EqualFold(a, email) {
+				return true
+			}
+		}
+		return false
+	}
+	if mentioned(c.MentionedEmailAddresses) {
+		return true
+	}
+	for _, r := range c.Replies {
+		if mentioned(r.MentionedEmailAddresses) {
+			return true
+		}
+	}
+	return false
+}
+
+// awaitingReply reports whether the user takes part in a comment thread (started it,
+// replied, was mentioned, or is assigned) and someone else wrote the latest message.
+// me may be empty, in which case mentions and assignments aren't considered.
+func awaitingReply(c *drive.Comment, me string) bool {
+	last := c.Author
+	involved := c.Author != nil && c.Author.Me
+	for _, r := range c.Replies {
+		if r.Author != nil && r.Author.Me {
+			involved = true
+		}
+		last = r.Author
+	}
+	if me != "" && (strings.EqualFold(c.AssigneeEmailAddress, me) || commentMentions(c, me)) {
+		involved = true
+	}
+	return involved && (last == nil || !last.Me)
+}
+
+// myEmail returns the email address of the authenticated user, cached after the first lookup.
+func (d *DocsTools) myEmail(ctx context.Context) (string, error) {
+	d.meMu.Lock()
+	defer d.meMu.Unlock()
+	if d.me != "" {
+		return d.me, nil
+	}
+	about, err := d.driveService.About.Get().Context(ctx).Fields("user(emailAddress)").Do()
+	if err != nil {
+		return "", err
+	}
+	if about.User == nil || about.User.EmailAddress == "" {
+		return "", fmt.Errorf("email address not available")
+	}
+	d.me = about.User.EmailAddress
+	return d.me, nil
+}
+
+// locateComments sets the Location of each comment whose quoted text is found in the document.
+func (d *DocsTools) locateComments(ctx context.Context, documentID string, comments []DocsComment) error {
+	locs, err := d.documentLocations(ctx, documentID)
+	if err != nil {
+		return err
+	}
+
+	for i := range comments {
+		if comments[i].quoteKey == "" {
+			continue
+		}
+		if loc, ok := locateQuote(locs, comments[i].quoteKey); ok {
+			comments[i].Location = &DocsCommentLocation{
+				TabID:    loc.tabID,
+				TabTitle: loc.tabTitle,
+				Heading:  loc.heading,
+				Link:     docLink(documentID, loc.tabID, loc.headingID),
+			}
+		}
+	}
+	return nil
+}
+
+// documentLocations fetches a document and returns the location of every paragraph in its tabs.
+func (d *DocsTools) documentLocations(ctx context.Context, documentID string) ([]textLocation, error) {
+	doc, err := d.docsService.Documents.Get(documentID).
+		IncludeTabsContent(true).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+
+	var locs []textLocation
+	if len(doc.Tabs) > 0 {
+		err = walkTabs(ctx, doc.Tabs, func(tab *docs.Tab) error {
+			if tab.DocumentTab.Body == nil {
+				return nil
+			}
+			cur := textLocation{tabID: tab.TabProperties.TabId, tabTitle: tab.TabProperties.Title}
+			if cur.tabTitle == "" {
+				cur.tabTitle = doc.Title
+			}
+			locs = appendLocations(locs, tab.DocumentTab.Body.Content, &cur, true)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else if doc.Body != nil {
+		cur := textLocation{tabTitle: doc.Title}
+		locs = appendLocations(locs, doc.Body.Content, &cur, true)
+	}
+	return locs, nil
+}
+
+// listOpenComments returns all unresolved comments on a document.
+func (d *DocsTools) listOpenComments(ctx context.Context, documentID string) ([]*drive.Comment, error) {
+	var comments []*drive.Comment
+	pageToken := ""
+	for {
+		if err := types.GlobalRateLimiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		call := d.driveService.Comments.List(documentID).
+			Context(ctx).
+			Fields("nextPageToken, comments(" + docsCommentFields + ")").
+			IncludeDeleted(false).
+			PageSize(100)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		list, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range list.Comments {
+			if !c.Resolved {
+				comments = append(comments, c)
+			}
+		}
+		if list.NextPageToken == "" {
+			return comments, nil
+		}
+		pageToken = list.NextPageToken
+	}
+}
+
+// convertComment converts a Drive comment and its replies to a DocsComment.
+func convertComment(c *drive.Comment) DocsComment {
+	comment := DocsComment{
+		ID:           c.Id,
+		Content:      c.Content,
+		CreatedTime:  c.CreatedTime,
+		ModifiedTime: c.ModifiedTime,
+		Resolved:     c.Resolved,
+	}
+
+	if c.Author != nil {
+		comment.Author = c.Author.DisplayName
+		comment.AuthorIsMe = c.Author.Me
+		comment.AuthorEmail = c.Author.EmailAddress
+	}
+	comment.Assignee = c.AssigneeEmailAddress
+	comment.Mentions = c.MentionedEmailAddresses
+
+	if c.QuotedFileContent != nil {
+		comment.QuotedText = c.QuotedFileContent.Value
+		comment.quoteKey = quoteKey(c)
+	}
+
+	for _, r := range c.Replies {
+		comment.Replies = append(comment.Replies, convertReply(r))
+	}
+
+	return comment
+}
+
+// convertReply converts a Drive reply to a DocsCommentReply.
+func convertReply(r *drive.Reply) DocsCommentReply {
+	reply := DocsCommentReply{
+		ID:          r.Id,
+		Content:     r.Content,
+		Mentions:    r.MentionedEmailAddresses,
+		CreatedTime: r.CreatedTime,
+	}
+	if r.Author != nil {
+		reply.Author = r.Author.DisplayName
+		reply.AuthorIsMe = r.Author.Me
+		reply.AuthorEmail = r.Author.EmailAddress
+	}
+	return reply
+}
+
+// sanitizeComment strips invisible characters from the text of a comment and its
+// replies, recording findings in c.Warnings.
+func sanitizeComment(c *DocsComment) {
+	c.Content = sanitizeUntrustedField("content", c.Content, &c.Warnings)
+	c.QuotedText = sanitizeUntrustedField("quoted_text", c.QuotedText, &c.Warnings)
+	for i := range c.Replies {
+		c.Replies[i].Content = sanitizeUntrustedField("reply "+c.Replies[i].ID, c.Replies[i].Content, &c.Warnings)
+	}
+}
+
+// docsReplyFields are the Drive reply fields used by convertReply.
+const docsReplyFields = "id, author, content, createdTime, mentionedEmailAddresses"
+
+// DocsAddCommentRequest contains arguments for adding a comment.
+type DocsAddCommentRequest struct {
+	DocumentID string `json:"document_id"`
+	Content    string `json:"content"`
+	QuotedText string `json:"quoted_text"` // Document text the comment refers to
+}
+
+// DocsReplyToCommentRequest contains arguments for replying to a comment.
+type DocsReplyToCommentRequest struct {
+	DocumentID string `json:"document_id"`
+	CommentID  string `json:"comment_id"`
+	Content    string `json:"content"`
+}
+
+// DocsResolveCommentRequest contains arguments for resolving a comment.
+type DocsResolveCommentRequest struct {
+	DocumentID string `json:"document_id"`
+	CommentID  string `json:"comment_id"`
+	Content    string `json:"content"` // Optional reply posted with the resolution
+}
+
+// AddCommentTool returns the tool definition for adding a comment.
+func (d *DocsTools) AddCommentTool() mcp.Tool {
+	return mcp.NewTool("docs_add_comment",
+		mcp.WithDescription(`Adds a comment to a Google Doc.
+
+With quoted_text, the comment records the document text it refers to, which must appear in the document. Google Docs shows comments added through the API alongside the document rather than highlighting the quoted text.`),
+		mcp.WithString("document_id",
+			mcp.Required(),
+			mcp.Description("The document ID"),
 		),
-		mcp.WithString("modified_after",
-			mcp.Description("Only include docs modified after this date (RFC3339 format)"),
+		mcp.WithString("content",
+			mcp.Required(),
+			mcp.Description("The comment text"),
 		),
-		mcp.WithString("modified_before",
-			mcp.Description("Only include docs modified before this date (RFC3339 format)"),
+		mcp.WithString("quoted_text",
+			mcp.Description("Text from the document that the comment refers to"),
 		),
 	)
 }
 
-// ListInFolderHandler handles docs_list_in_folder tool calls.
-func (d *DocsTools) ListInFolderHandler(ctx context.Context, request mcp.CallToolRequest, args DocsListInFolderRequest) (*mcp.CallToolResult, error) {
-	folderID := args.FolderID
-	if folderID == "" {
-		folderID = "root"
+// AddCommentHandler handles docs_add_comment tool calls.
+func (d *DocsTools) AddCommentHandler(ctx context.Context, request mcp.CallToolRequest, args DocsAddCommentRequest) (*mcp.CallToolResult, error) {
+	if args.DocumentID == "" {
+		return mcp.NewToolResultError("document_id is required"), nil
+	}
+	if strings.TrimSpace(args.Content) == "" {
+		return mcp.NewToolResultError("content is required"), nil
 	}
 
-	pageSize := args.PageSize
-	if pageSize <= 0 {
-		pageSize = 100
+	if err := d.checkDocumentPolicy(ctx, args.DocumentID); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	// Build query: docs in folder, exclude trashed
-	q := fmt.Sprintf("'%s' in parents and mimeType='application/vnd.google-apps.document' and trashed=false", folderID)
+	comment := &drive.Comment{Content: args.Content}
+	if strings.TrimSpace(args.QuotedText) != "" {
+		comment.QuotedFileContent = &drive.CommentQuotedFileContent{
+			MimeType: "text/plain",
+			Value:    args.QuotedText,
+		}
+		// Catch quotes that don't match the document before publishing the comment
+		locs, err := d.documentLocations(ctx, args.DocumentID)
+		if err != nil {
+			return mcp.NewToolResultError("failed to get document: " + err.Error()), nil
+		}
+		if _, ok := locateQuote(locs, quoteKey(comment)); !ok {
+			return mcp.NewToolResultError("quoted_text was not found in the document"), nil
+		}
+	}
 
-	// Add date filters
-	if args.ModifiedAfter != "" {
-		q += fmt.Sprintf(" and modifiedTime > '%s'", args.ModifiedAfter)
+	created, err := d.driveWriteService.Comments.Create(args.DocumentID, comment).
+		Context(ctx).
+		Fields(googleapi.Field(docsCommentFields)).
+		Do()
+	if err != nil {
+		return mcp.NewToolResultError("failed to add comment: " + err.Error()), nil
 	}
-	if args.ModifiedBefore != "" {
-		q += fmt.Sprintf(" and modifiedTime < '%s'", args.ModifiedBefore)
+
+	data, err := types.MarshalResponse(convertComment(created))
+	if err != nil {
+		return mcp.NewToolResultError("failed to marshal response: " + err.Error()), nil
 	}
+	return mcp.NewToolResultText(data), nil
+}
 
-	call := d.driveService.Files.List().
-		Context(ctx).
-		Q(q).
-		PageSize(int64(pageSize)).
-		Fields("nextPageToken, files(id, name, createdTime, modifiedTime, webViewLink)").
-		SupportsAllDrives(true).
-		IncludeItemsFromAllDrives(true)
+// ReplyToCommentTool returns the tool definition for replying to a comment.
+func (d *DocsTools) ReplyToCommentTool() mcp.Tool {
+	return mcp.NewTool("docs_reply_to_comment",
+		mcp.WithDescription(`Replies to a comment on a Google Doc.`),
+		mcp.WithString("document_id",
+			mcp.Required(),
+			mcp.Description("The document ID"),
+		),
+		mcp.WithString("comment_id",
+			mcp.Required(),
+			mcp.Description("The comment ID (from docs_get_comments results)"),
+		),
+		mcp.WithString("content",
+			mcp.Required(),
+			mcp.Description("The reply text"),
+		),
+	)
+}
 
-	// Apply pagination
-	if args.PageToken != "" {
-		call = call.PageToken(args.PageToken)
+// ReplyToCommentHandler handles docs_reply_to_comment tool calls.
+func (d *DocsTools) ReplyToCommentHandler(ctx context.Context, request mcp.CallToolRequest, args DocsReplyToCommentRequest) (*mcp.CallToolResult, error) {
+	if args.DocumentID == "" {
+		return mcp.NewToolResultError("document_id is required"), nil
 	}
-	// Apply sorting
-	if args.OrderBy != "" {
-		call = call.OrderBy(args.OrderBy)
+	if args.CommentID == "" {
+		return mcp.NewToolResultError("comment_id is required"), nil
 	}
-
-	fileList, err := call.Do()
-	if err != nil {
-		return mcp.NewToolResultError("failed to list documents: " + err.Error()), nil
+	if strings.TrimSpace(args.Content) == "" {
+		return mcp.NewToolResultError("content is required"), nil
 	}
 
-	results := make([]DocsSearchResult, 0, len(fileList.Files))
-	for _, f := range fileList.Files {
-		results = append(results, DocsSearchResult{
-			ID:    f.Id,
-			Title: f.Name,
-		})
+	if err := d.checkDocumentPolicy(ctx, args.DocumentID); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	response := DocsSearchResponse{
-		Results:       results,
-		NextPageToken: fileList.NextPageToken,
+	reply, err := d.driveWriteService.Replies.Create(args.DocumentID, args.CommentID, &drive.Reply{Content: args.Content}).
+		Context(ctx).
+		Fields(googleapi.Field(docsReplyFields)).
+		Do()
+	if err != nil {
+		return mcp.NewToolResultError("failed to reply to comment: " + err.Error()), nil
 	}
 
-	data, err := types.MarshalResponse(response)
+	data, err := types.MarshalResponse(convertReply(reply))
 	if err != nil {
 		return mcp.NewToolResultError("failed to marshal response: " + err.Error()), nil
 	}
 	return mcp.NewToolResultText(data), nil
 }
 
-// GetCommentsTool returns the tool definition for fetching document comments.
-func (d *DocsTools) GetCommentsTool() mcp.Tool {
-	return mcp.NewTool("docs_get_comments",
-		mcp.WithDescription(`Retrieves comments and replies from a Google Doc.`),
+// ResolveCommentTool returns the tool definition for resolving a comment.
+func (d *DocsTools) ResolveCommentTool() mcp.Tool {
+	return mcp.NewTool("docs_resolve_comment",
+		mcp.WithDescription(`Resolves a comment on a Google Doc, optionally with a closing reply. Returns the updated comment.`),
 		mcp.WithString("document_id",
 			mcp.Required(),
 			mcp.Description("The document ID"),
 		),
-		mcp.WithBoolean("include_resolved",
-			mcp.Description("Include resolved comments (default false, only shows open comments)"),
-		),
-		mcp.WithString("page_token",
-			mcp.Description("Page token from previous response to continue pagination"),
-		),
-		mcp.WithNumber("page_size",
-			mcp.Description("Maximum number of comments per page (default 100)"),
-			mcp.Min(1),
-			mcp.Max(100),
+		mcp.WithString("comment_id",
+			mcp.Required(),
+			mcp.Description("The comment ID (from docs_get_comments results)"),
 		),
-		mcp.WithString("modified_after",
-			mcp.Description("Only include comments modified after this date (RFC3339 format)"),
+		mcp.WithString("content",
+			mcp.Description("Optional reply to post when resolving"),
 		),
 	)
 }
 
-// DocsComment represents a comment on a document.
-type DocsComment struct {
-	ID           string             `json:"id"`
-	Author       string             `json:"author"`
-	AuthorIsMe   bool               `json:"author_is_me"`
-	Content      string             `json:"content"`
-	QuotedText   string             `json:"quoted_text,omitempty"`
-	CreatedTime  string             `json:"created_time"`
-	ModifiedTime string             `json:"modified_time,omitempty"`
-	Resolved     bool               `json:"resolved"`
-	Replies      []DocsCommentReply `json:"replies,omitempty"`
+// ResolveCommentHandler handles docs_resolve_comment tool calls.
+func (d *DocsTools) ResolveCommentHandler(ctx context.Context, request mcp.CallToolRequest, args DocsResolveCommentRequest) (*mcp.CallToolResult, error) {
+	if args.DocumentID == "" {
+		return mcp.NewToolResultError("document_id is required"), nil
+	}
+	if args.CommentID == "" {
+		return mcp.NewToolResultError("comment_id is required"), nil
+	}
+
+	if err := d.checkDocumentPolicy(ctx, args.DocumentID); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Comments are resolved by posting a reply with the resolve action
+	_, err := d.driveWriteService.Replies.Create(args.DocumentID, args.CommentID, &drive.Reply{
+		Action:  "resolve",
+		Content: args.Content,
+	}).
+		Context(ctx).
+		Fields("id").
+		Do()
+	if err != nil {
+		return mcp.NewToolResultError("failed to resolve comment: " + err.Error()), nil
+	}
+
+	comment, err := d.driveService.Comments.Get(args.DocumentID, args.CommentID).
+		Context(ctx).
+		Fields(googleapi.Field(docsCommentFields)).
+		Do()
+	if err != nil {
+		return mcp.NewToolResultError("failed to get comment: " + err.Error()), nil
+	}
+
+	data, err := types.MarshalResponse(convertComment(comment))
+	if err != nil {
+		return mcp.NewToolResultError("failed to marshal response: " + err.Error()), nil
+	}
+	return mcp.NewToolResultText(data), nil
 }
 
-// DocsCommentReply represents a reply to a comment.
-type DocsCommentReply struct {
-	ID          string `json:"id"`
-	Author      string `json:"author"`
-	AuthorIsMe  bool   `json:"author_is_me"`
-	Content     string `json:"content"`
-	CreatedTime string `json:"created_time"`
+// maxBatchUpdateRequests caps the number of requests sent in one Docs batchUpdate call.
+const maxBatchUpdateRequests = 500
+
+// defaultDocumentTitle is the title of created documents with no title or heading.
+const defaultDocumentTitle = "Untitled document"
+
+// DocsCreateFromMarkdownRequest contains arguments for creating a document from Markdown.
+type DocsCreateFromMarkdownRequest struct {
+	Markdown string `json:"markdown"`
+	Title    string `json:"title"`     // Defaults to the first heading
+	FolderID string `json:"folder_id"` // Defaults to My Drive
 }
 
-// DocsGetCommentsResponse contains the comments for a document.
-type DocsGetCommentsResponse struct {
-	DocumentID    string        `json:"document_id"`
-	Comments      []DocsComment `json:"comments"`
-	NextPageToken string        `json:"next_page_token,omitempty"`
+// DocsCreateFromMarkdownResponse is the response for docs_create_from_markdown.
+type DocsCreateFromMarkdownResponse struct {
+	DocID    string   `json:"doc_id"`
+	DocTitle string   `json:"doc_title"`
+	Link     string   `json:"link"`
+	Notes    []string `json:"notes,omitempty"` // Markdown that couldn't be reproduced exactly
 }
 
-// GetCommentsHandler handles docs_get_comments tool calls.
-func (d *DocsTools) GetCommentsHandler(ctx context.Context, request mcp.CallToolRequest, args DocsGetCommentsRequest) (*mcp.CallToolResult, error) {
-	if args.DocumentID == "" {
-		return mcp.NewToolResultError("document_id is required"), nil
-	}
+// CreateFromMarkdownTool returns the tool definition for creating a document from Markdown.
+func (d *DocsTools) CreateFromMarkdownTool() mcp.Tool {
+	return mcp.NewTool("docs_create_from_markdown",
+		mcp.WithDescription(`Creates a Google Doc from Markdown (CommonMark with GitHub tables, task lists, and strikethrough).
 
-	call := d.driveService.Comments.List(args.DocumentID).
-		Context(ctx).
-		Fields("nextPageToken, comments(id, author, content, quotedFileContent, createdTime, modifiedTime, resolved, replies)").
-		IncludeDeleted(false)
+Headings, paragraphs, emphasis, strikethrough, links, inline code, code blocks, ordered, unordered, and task lists, tables, and horizontal rules are converted to native Docs formatting, which docs_get_content reads back as Markdown. Some conversions are lossy: block quotes become indented paragraphs (read back as plain paragraphs), a paragraph that is only inline code is read back as a code block, code block languages are dropped, nested lists take the numbered or bulleted style of their outermost list, images become links, and HTML is inserted as plain text. The response notes the lossy conversions it made.`),
+		mcp.WithString("markdown",
+			mcp.Required(),
+			mcp.Description("The document content as Markdown"),
+		),
+		mcp.WithString("title",
+			mcp.Description("The document title (default: the first heading, or \"Untitled document\")"),
+		),
+		mcp.WithString("folder_id",
+			mcp.Description("ID of the folder to create the document in (default: My Drive)"),
+		),
+	)
+}
 
-	// Apply pagination
-	if args.PageToken != "" {
-		call = call.PageToken(args.PageToken)
+// CreateFromMarkdownHandler handles docs_create_from_markdown tool calls.
+func (d *DocsTools) CreateFromMarkdownHandler(ctx context.Context, request mcp.CallToolRequest, args DocsCreateFromMarkdownRequest) (*mcp.CallToolResult, error) {
+	if strings.TrimSpace(args.Markdown) == "" {
+		return mcp.NewToolResultError("markdown is required"), nil
 	}
-	// Apply page size
-	if args.PageSize > 0 {
-		call = call.PageSize(int64(args.PageSize))
-	} else {
-		call = call.PageSize(100)
+	if args.FolderID != "" {
+		if err := validateDriveID(args.FolderID, "folder_id"); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
+	requests, heading, notes := markdownToDocsRequests(args.Markdown)
+	title := strings.TrimSpace(args.Title)
+	if title == "" {
+		title = heading
 	}
-	// Apply modified after filter (API supports startModifiedTime)
-	if args.ModifiedAfter != "" {
-		call = call.StartModifiedTime(args.ModifiedAfter)
+	if title == "" {
+		title = defaultDocumentTitle
 	}
 
-	commentList, err := call.Do()
-	if err != nil {
-		return mcp.NewToolResultError("failed to get comments: " + err.Error()), nil
+	var parents []string
+	if args.FolderID != "" {
+		parents = []string{args.FolderID}
 	}
 
-	var comments []DocsComment
-	for _, c := range commentList.Comments {
-		// Skip resolved comments unless requested
-		if c.Resolved && !args.IncludeResolved {
-			continue
+	// Check the document as it will be created, owned by the current user
+	if types.GlobalPolicy.Docs.Enabled() {
+		me, err := d.myEmail(ctx)
+		if err != nil {
+			return mcp.NewToolResultError("failed to get user email: " + err.Error()), nil
 		}
-
-		comment := DocsComment{
-			ID:           c.Id,
-			Content:      c.Content,
-			CreatedTime:  c.CreatedTime,
-			ModifiedTime: c.ModifiedTime,
-			Resolved:     c.Resolved,
+		f := &drive.File{Parents: parents, Owners: []*drive.User{{EmailAddress: me}}}
+		if err := d.checkFilePolicy(ctx, f); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
+	}
 
-		if c.Author != nil {
-			comment.Author = c.Author.DisplayName
-			comment.AuthorIsMe = c.Author.Me
-		}
+	created, err := d.driveWriteService.Files.Create(&drive.File{
+		Name:     title,
+		MimeType: docsMimeType,
+		Parents:  parents,
+	}).
+		Context(ctx).
+		Fields("id, name").
+		SupportsAllDrives(true).
+		Do()
+	if err != nil {
+		return mcp.NewToolResultError("failed to create document: " + err.Error()), nil
+	}
 
-		if c.QuotedFileContent != nil {
-			comment.QuotedText = c.QuotedFileContent.Value
+	for start := 0; start < len(requests); start += maxBatchUpdateRequests {
+		if err := types.GlobalRateLimiter.Wait(ctx); err != nil {
+			d.trashFile(created.Id)
+			return mcp.NewToolResultError("failed to write document: " + err.Error()), nil
 		}
-
-		for _, r := range c.Replies {
-			reply := DocsCommentReply{
-				ID:          r.Id,
-				Content:     r.Content,
-				CreatedTime: r.CreatedTime,
-			}
-			if r.Author != nil {
-				reply.Author = r.Author.DisplayName
-				reply.AuthorIsMe = r.Author.Me
-			}
-			comment.Replies = append(comment.Replies, reply)
+		batch := requests[start:min(start+maxBatchUpdateRequests, len(requests))]
+		_, err := d.docsWriteService.Documents.BatchUpdate(created.Id, &docs.BatchUpdateDocumentRequest{Requests: batch}).
+			Context(ctx).
+			Do()
+		if err != nil {
+			// Don't leave a partially written document behind
+			d.trashFile(created.Id)
+			return mcp.NewToolResultError("failed to write document: " + err.Error()), nil
 		}
-
-		comments = append(comments, comment)
 	}
 
-	response := DocsGetCommentsResponse{
-		DocumentID:    args.DocumentID,
-		Comments:      comments,
-		NextPageToken: commentList.NextPageToken,
+	response := DocsCreateFromMarkdownResponse{
+		DocID:    created.Id,
+		DocTitle: created.Name,
+		Link:     docLink(created.Id, "", ""),
+		Notes:    notes,
 	}
-
 	data, err := types.MarshalResponse(response)
 	if err != nil {
 		return mcp.NewToolResultError("failed to marshal response: " + err.Error()), nil
@@ -791,6 +3744,17 @@ func (d *DocsTools) GetCommentsHandler(ctx context.Context, request mcp.CallTool
 	return mcp.NewToolResultText(data), nil
 }
 
+// trashFile moves a file to the trash, ignoring errors. It is used to clean up after
+// failures, so it runs even if the request context was cancelled.
+func (d *DocsTools) trashFile(fileID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	_, _ = d.driveWriteService.Files.Update(fileID, &drive.File{Trashed: true}).
+		Context(ctx).
+		SupportsAllDrives(true).
+		Do()
+}
+
 // MarshalCompact returns a compact text representation of the document content.
 func (d DocsGetContentResponse) MarshalCompact() string {
 	var sb strings.Builder
@@ -809,11 +3773,66 @@ func (d DocsGetContentResponse) MarshalCompact() string {
 			sb.WriteString(")")
 		}
 		sb.WriteString(" ---\n")
+		for _, w := range tab.Warnings {
+			sb.WriteString("Warning: ")
+			sb.WriteString(w)
+			sb.WriteString("\n")
+		}
 		sb.WriteString(tab.TabMarkdown)
 		if !strings.HasSuffix(tab.TabMarkdown, "\n") {
 			sb.WriteString("\n")
 		}
 	}
+
+	if len(d.Comments) > 0 {
+		sb.WriteString("\n--- Comments not found in the text ---\n")
+		sb.WriteString(DocsGetCommentsResponse{Comments: d.Comments}.MarshalCompact())
+		sb.WriteString("\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// MarshalCompact returns a compact text representation of the document outline.
+func (d DocsGetOutlineResponse) MarshalCompact() string {
+	var sb strings.Builder
+	sb.WriteString("=== Outline: ")
+	sb.WriteString(d.DocTitle)
+	sb.WriteString(" ===\nID: ")
+	sb.WriteString(d.DocID)
+	sb.WriteString("\n")
+
+	var writeHeadings func(headings []*DocsOutlineHeading, depth int)
+	writeHeadings = func(headings []*DocsOutlineHeading, depth int) {
+		for _, h := range headings {
+			sb.WriteString(strings.Repeat("  ", depth))
+			sb.WriteString("- ")
+			sb.WriteString(h.Text)
+			fmt.Fprintf(&sb, " (%d words", h.Words)
+			if h.HeadingID != "" {
+				sb.WriteString(", id: ")
+				sb.WriteString(h.HeadingID)
+			}
+			sb.WriteString(")\n")
+			writeHeadings(h.Children, depth+1)
+		}
+	}
+
+	for _, tab := range d.Tabs {
+		sb.WriteString("\n--- Tab: ")
+		sb.WriteString(tab.TabTitle)
+		if tab.TabID != "" {
+			sb.WriteString(" (id: ")
+			sb.WriteString(tab.TabID)
+			sb.WriteString(")")
+		}
+		fmt.Fprintf(&sb, " --- %d words\n", tab.Words)
+		for _, w := range tab.Warnings {
+			sb.WriteString("Warning: ")
+			sb.WriteString(w)
+			sb.WriteString("\n")
+		}
+		writeHeadings(tab.Headings, 0)
+	}
 	return strings.TrimSuffix(sb.String(), "\n")
 }
 
@@ -825,55 +3844,178 @@ func (d DocsGetCommentsResponse) MarshalCompact() string {
 		if i > 0 {
 			sb.WriteString("\n")
 		}
-		// Comment header: "Comment <id> by <author> at <time> [resolved]"
-		sb.WriteString("Comment ")
-		sb.WriteString(c.ID)
-		sb.WriteString(" by ")
-		if c.AuthorIsMe {
-			sb.WriteString("Me")
-		} else {
-			sb.WriteString(c.Author)
-		}
-		sb.WriteString(" at ")
-		sb.WriteString(c.CreatedTime)
-		if c.Resolved {
-			sb.WriteString(" [resolved]")
-		}
-		sb.WriteString("\n")
+		writeCompactComment(&sb, c)
+	}
 
-		// Quoted text
-		if c.QuotedText != "" {
-			sb.WriteString("> ")
-			sb.WriteString(strings.ReplaceAll(c.QuotedText, "\n", "\n> "))
-			sb.WriteString("\n")
-		}
+	if d.Truncated {
+		sb.WriteString("\n(truncated at max_items)")
+	}
+	if d.NextPageToken != "" {
+		sb.WriteString("\nNext Page Token: ")
+		sb.WriteString(d.NextPageToken)
+	}
 
-		// Comment content
-		sb.WriteString(c.Content)
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// writeCompactComment writes a comment and its replies in compact format.
+func writeCompactComment(sb *strings.Builder, c DocsComment) {
+	// Comment header: "Comment <id> by <author> at <time> [resolved]"
+	sb.WriteString("Comment ")
+	sb.WriteString(c.ID)
+	sb.WriteString(" by ")
+	if c.AuthorIsMe {
+		sb.WriteString("Me")
+	} else {
+		sb.WriteString(c.Author)
+	}
+	sb.WriteString(" at ")
+	sb.WriteString(c.CreatedTime)
+	if c.Resolved {
+		sb.WriteString(" [resolved]")
+	}
+	if c.Assignee != "" {
+		sb.WriteString(" [assigned to ")
+		sb.WriteString(c.Assignee)
+		sb.WriteString("]")
+	}
+	sb.WriteString("\n")
+
+	if c.Location != nil {
+		sb.WriteString("Location: ")
+		sb.WriteString(sectionPath(c.Location.TabTitle, []string{c.Location.Heading}))
+		sb.WriteString(" (")
+		sb.WriteString(c.Location.Link)
+		sb.WriteString(")\n")
+	}
+	for _, w := range c.Warnings {
+		sb.WriteString("Warning: ")
+		sb.WriteString(w)
 		sb.WriteString("\n")
+	}
 
-		// Replies
-		for _, r := range c.Replies {
-			sb.WriteString("  Reply ")
-			sb.WriteString(r.ID)
-			sb.WriteString(" by ")
-			if r.AuthorIsMe {
-				sb.WriteString("Me")
-			} else {
-				sb.WriteString(r.Author)
-			}
-			sb.WriteString(" at ")
-			sb.WriteString(r.CreatedTime)
-			sb.WriteString("\n  ")
-			sb.WriteString(strings.ReplaceAll(r.Content, "\n", "\n  "))
-			sb.WriteString("\n")
-		}
+	// Quoted text
+	if c.QuotedText != "" {
+		sb.WriteString("> ")
+		sb.WriteString(strings.ReplaceAll(c.QuotedText, "\n", "\n> "))
+		sb.WriteString("\n")
 	}
 
+	// Comment content
+	sb.WriteString(c.Content)
+	sb.WriteString("\n")
+
+	// Replies
+	for _, r := range c.Replies {
+		writeCompactReply(sb, r, "  ")
+	}
+}
+
+// writeCompactReply writes a reply in compact format, indented by indent.
+func writeCompactReply(sb *strings.Builder, r DocsCommentReply, indent string) {
+	sb.WriteString(indent)
+	sb.WriteString("Reply ")
+	sb.WriteString(r.ID)
+	sb.WriteString(" by ")
+	if r.AuthorIsMe {
+		sb.WriteString("Me")
+	} else {
+		sb.WriteString(r.Author)
+	}
+	sb.WriteString(" at ")
+	sb.WriteString(r.CreatedTime)
+	sb.WriteString("\n")
+	sb.WriteString(indent)
+	sb.WriteString(strings.ReplaceAll(r.Content, "\n", "\n"+indent))
+	sb.WriteString("\n")
+}
+
+// MarshalCompact returns a compact text representation of a single comment.
+func (c DocsComment) MarshalCompact() string {
+	var sb strings.Builder
+	writeCompactComment(&sb, c)
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// MarshalCompact returns a compact text representation of a single reply.
+func (r DocsCommentReply) MarshalCompact() string {
+	var sb strings.Builder
+	writeCompactReply(&sb, r, "")
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// MarshalCompact returns a compact text representation of the revision list.
+// Format: one revision per line as "id | modifiedTime | author", with optional
+// "Next Page Token: <token>" appended if pagination continues.
+func (d DocsListRevisionsResponse) MarshalCompact() string {
+	var sb strings.Builder
+	for _, r := range d.Revisions {
+		sb.WriteString(r.ID)
+		sb.WriteString(" | ")
+		sb.WriteString(r.ModifiedTime)
+		sb.WriteString(" | ")
+		if r.AuthorIsMe {
+			sb.WriteString("Me")
+		} else {
+			sb.WriteString(r.Author)
+		}
+		if r.KeepForever {
+			sb.WriteString(" [kept forever]")
+		}
+		sb.WriteString("\n")
+	}
+	if d.Truncated {
+		sb.WriteString("\n(truncated at max_items)")
+	}
 	if d.NextPageToken != "" {
 		sb.WriteString("\nNext Page Token: ")
 		sb.WriteString(d.NextPageToken)
 	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
 
+// MarshalCompact returns a compact text representation of the diff.
+func (d DocsDiffResponse) MarshalCompact() string {
+	var sb strings.Builder
+	sb.WriteString("=== Diff: ")
+	if d.DocTitle != "" {
+		sb.WriteString(d.DocTitle)
+	} else {
+		sb.WriteString(d.DocumentID)
+	}
+	sb.WriteString(" ===\nFrom: ")
+	sb.WriteString(d.From)
+	sb.WriteString("\nTo: ")
+	sb.WriteString(d.To)
+	fmt.Fprintf(&sb, "\nChanges: +%d -%d lines\n", d.Added, d.Removed)
+	if d.Note != "" {
+		sb.WriteString(d.Note)
+		sb.WriteString("\n")
+	}
+	for _, w := range d.Warnings {
+		sb.WriteString("Warning: ")
+		sb.WriteString(w)
+		sb.WriteString("\n")
+	}
+	if d.Diff != "" {
+		sb.WriteString("\n")
+		sb.WriteString(d.Diff)
+	}
 	return strings.TrimSuffix(sb.String(), "\n")
 }
+
+// MarshalCompact returns a compact text representation of the created document.
+func (d DocsCreateFromMarkdownResponse) MarshalCompact() string {
+	var sb strings.Builder
+	sb.WriteString("=== Created: ")
+	sb.WriteString(d.DocTitle)
+	sb.WriteString(" ===\nID: ")
+	sb.WriteString(d.DocID)
+	sb.WriteString("\nLink: ")
+	sb.WriteString(d.Link)
+	for _, n := range d.Notes {
+		sb.WriteString("\nNote: ")
+		sb.WriteString(n)
+	}
+	return sb.String()
+}
@@ -0,0 +1,815 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+
+	"github.com/joelanford/mcp/google-workspace-mcp/types"
+)
+
+// driveFileFields are the Drive file fields returned by the list tools.
+const driveFileFields = "id, name, mimeType, size, owners(emailAddress), parents, modifiedTime, webViewLink, shared, driveId, shortcutDetails(targetId, targetMimeType)"
+
+// driveMetadataFields are the Drive file fields returned by drive_get_metadata.
+const driveMetadataFields = driveFileFields + ", description, createdTime, trashed, starred, lastModifyingUser(displayName, emailAddress), sharingUser(displayName, emailAddress), permissions(type, role, emailAddress, domain, displayName)"
+
+// driveCorpora are the accepted values of the corpora argument.
+var driveCorpora = map[string]bool{"user": true, "drive": true, "domain": true, "allDrives": true}
+
+// DriveSearchRequest contains arguments for searching Drive files.
+type DriveSearchRequest struct {
+	Query          string            `json:"query"`
+	SearchMode     string            `json:"search_mode"` // name, fullText, or both
+	MimeType       string            `json:"mime_type"`   // Exact MIME type filter
+	PageSize       int               `json:"page_size"`
+	PageToken      string            `json:"page_token"`      // Continue from previous page
+	OrderBy        string            `json:"order_by"`        // Sort order: createdTime, modifiedTime, name, etc.
+	ModifiedAfter  string            `json:"modified_after"`  // RFC3339 date - only files modified after this time
+	ModifiedBefore string            `json:"modified_before"` // RFC3339 date - only files modified before this time
+	OwnerEmail     string            `json:"owner_email"`     // Filter to files owned by this email
+	DriveID        string            `json:"drive_id"`        // Only search this shared drive
+	Corpora        string            `json:"corpora"`         // user, drive, domain, or allDrives
+	DriveQuery     *DriveQueryFilter `json:"drive_query"`     // Advanced structured filters
+	FetchAll       bool              `json:"fetch_all"`       // Follow page tokens and merge all pages
+	MaxItems       int               `json:"max_items"`       // Cap on total results when FetchAll is set
+}
+
+// DriveListFolderRequest contains arguments for listing a Drive folder.
+type DriveListFolderRequest struct {
+	FolderID   string            `json:"folder_id"`
+	PageSize   int               `json:"page_size"`
+	PageToken  string            `json:"page_token"`  // Continue from previous page
+	OrderBy    string            `json:"order_by"`    // Sort order: folder, modifiedTime, name, etc.
+	DriveID    string            `json:"drive_id"`    // Shared drive containing the folder
+	DriveQuery *DriveQueryFilter `json:"drive_query"` // Advanced structured filters
+	FetchAll   bool              `json:"fetch_all"`   // Follow page tokens and merge all pages
+	MaxItems   int               `json:"max_items"`   // Cap on total results when FetchAll is set
+}
+
+// DriveGetMetadataRequest contains arguments for getting a file's metadata.
+type DriveGetMetadataRequest struct {
+	FileID string `json:"file_id"`
+}
+
+// DriveFile represents a Drive file of any type.
+type DriveFile struct {
+	ID                     string   `json:"id"`
+	Name                   string   `json:"name"`
+	MimeType               string   `json:"mimeType"`
+	Size                   int64    `json:"size,omitempty"` // Bytes; not set for Google Docs, Sheets, etc.
+	Owners                 []string `json:"owners,omitempty"`
+	Parents                []string `json:"parents,omitempty"`
+	ModifiedTime           string   `json:"modifiedTime,omitempty"`
+	WebViewLink            string   `json:"webViewLink,omitempty"`
+	Shared                 bool     `json:"shared"`
+	DriveID                string   `json:"driveId,omitempty"`                // Shared drive containing the file
+	ShortcutTargetID       string   `json:"shortcutTargetId,omitempty"`       // For shortcuts
+	ShortcutTargetMimeType string   `json:"shortcutTargetMimeType,omitempty"` // For shortcuts
+}
+
+// DriveListResponse contains a page of Drive files.
+type DriveListResponse struct {
+	Files         []DriveFile `json:"files"`
+	NextPageToken string      `json:"next_page_token,omitempty"`
+	Truncated     bool        `json:"truncated,omitempty"` // fetch_all stopped at max_items
+}
+
+// DrivePermission describes who a file is shared with.
+type DrivePermission struct {
+	Type   string `json:"type"` // user, group, domain, or anyone
+	Role   string `json:"role"` // owner, organizer, fileOrganizer, writer, commenter, or reader
+	Email  string `json:"email,omitempty"`
+	Domain string `json:"domain,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+// DriveFileMetadata contains detailed metadata for a single file.
+type DriveFileMetadata struct {
+	DriveFile
+	Description    string            `json:"description,omitempty"`
+	CreatedTime    string            `json:"createdTime,omitempty"`
+	Trashed        bool              `json:"trashed,omitempty"`
+	Starred        bool              `json:"starred,omitempty"`
+	LastModifiedBy string            `json:"lastModifiedBy,omitempty"`
+	SharedBy       string            `json:"sharedBy,omitempty"`    // Who shared the file with you
+	Permissions    []DrivePermission `json:"permissions,omitempty"` // Only visible if you can share the file
+}
+
+// DriveTools provides Google Drive API tools.
+type DriveTools struct {
+	driveService *drive.Service
+
+	*filePolicy
+}
+
+// NewDriveTools creates a new DriveTools instance from the provided clients.
+func NewDriveTools(clients *types.DriveClients) *DriveTools {
+	return &DriveTools{
+		driveService: clients.Drive,
+		filePolicy:   newFilePolicy(clients.Drive),
+	}
+}
+
+// SearchTool returns the tool definition for searching Drive files.
+func (d *DriveTools) SearchTool() mcp.Tool {
+	return mcp.NewTool("drive_search",
+		mcp.WithDescription(`Searches Google Drive for files of any type (Docs, Sheets, Slides, PDFs, images, folders, shortcuts).
+
+Returns each file's ID, name, MIME type, size, owners, parents, modified time, link, and sharing state.`),
+		mcp.WithString("query",
+			mcp.Description("Search string to find in file names or content (omit to match all files)"),
+		),
+		mcp.WithString("search_mode",
+			mcp.Description("Where to search: name (default), fullText (file content), or both. order_by is only supported with name"),
+			mcp.Enum("name", "fullText", "both"),
+		),
+		mcp.WithString("mime_type",
+			mcp.Description("Only include files of this MIME type, e.g. application/pdf, application/vnd.google-apps.spreadsheet, or application/vnd.google-apps.folder"),
+		),
+		mcp.WithNumber("page_size",
+			mcp.Description("Maximum number of results to return (default 25)"),
+			mcp.Min(1),
+			mcp.Max(1000),
+		),
+		mcp.WithString("page_token",
+			mcp.Description("Page token from previous response to continue pagination"),
+		),
+		mcp.WithString("order_by",
+			mcp.Description("Sort order: createdTime, folder, modifiedTime, name, name_natural, quotaBytesUsed, starred, viewedByMeTime (append ' desc' for descending)"),
+		),
+		mcp.WithString("modified_after",
+			mcp.Description("Only include files modified after this date (RFC3339 format, e.g. '2025-01-01T00:00:00Z')"),
+		),
+		mcp.WithString("modified_before",
+			mcp.Description("Only include files modified before this date (RFC3339 format)"),
+		),
+		mcp.WithString("owner_email",
+			mcp.Description("Only include files owned by this email address"),
+		),
+		mcp.WithString("drive_id",
+			mcp.Description("Only search this shared drive (sets corpora to drive)"),
+		),
+		mcp.WithString("corpora",
+			mcp.Description("Which files to search: user (default), drive (requires drive_id), domain, or allDrives"),
+			mcp.Enum("user", "drive", "domain", "allDrives"),
+		),
+		withDriveQuery(true),
+		withFetchAll(),
+	)
+}
+
+// SearchHandler handles drive_search tool calls.
+func (d *DriveTools) SearchHandler(ctx context.Context, request mcp.CallToolRequest, args DriveSearchRequest) (*mcp.CallToolResult, error) {
+	pageSize := args.PageSize
+	if pageSize <= 0 {
+		pageSize = 25
+		// Use larger pages when following page tokens to save round trips
+		if args.FetchAll {
+			pageSize = 100
+		}
+	}
+
+	q := &driveQuery{}
+	if args.Query != "" {
+		if err := q.search(args.SearchMode, args.Query, args.OrderBy); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+	if args.MimeType != "" {
+		q.equals("mimeType", args.MimeType)
+	}
+	q.boolean("trashed", false)
+
+	if err := q.timeAfter("modifiedTime", args.ModifiedAfter, "modified_after"); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := q.timeBefore("modifiedTime", args.ModifiedBefore, "modified_before"); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if args.OwnerEmail != "" {
+		q.in(args.OwnerEmail, "owners")
+	}
+	if err := args.DriveQuery.apply(q, true); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	response, err := d.listFiles(ctx, request, driveListOptions{
+		q:         q.String(),
+		orderBy:   args.OrderBy,
+		pageToken: args.PageToken,
+		pageSize:  pageSize,
+		driveID:   args.DriveID,
+		corpora:   args.Corpora,
+		fetchAll:  args.FetchAll,
+		maxItems:  args.MaxItems,
+	})
+	if err != nil {
+		return mcp.NewToolResultError("failed to search files: " + err.Error()), nil
+	}
+
+	data, err := types.MarshalResponse(response)
+	if err != nil {
+		return mcp.NewToolResultError("failed to marshal response: " + err.Error()), nil
+	}
+	return mcp.NewToolResultText(data), nil
+}
+
+// ListFolderTool returns the tool definition for listing a Drive folder.
+func (d *DriveTools) ListFolderTool() mcp.Tool {
+	return mcp.NewTool("drive_list_folder",
+		mcp.WithDescription(`Lists all files in a Google Drive folder, of any type (Docs, Sheets, Slides, PDFs, images, subfolders, shortcuts).
+
+Returns each file's ID, name, MIME type, size, owners, parents, modified time, link, and sharing state.`),
+		mcp.WithString("folder_id",
+			mcp.Description("The folder ID (defaults to 'root' for My Drive; use a shared drive ID to list the top of a shared drive)"),
+		),
+		mcp.WithNumber("page_size",
+			mcp.Description("Maximum number of results to return (default 100)"),
+			mcp.Min(1),
+			mcp.Max(1000),
+		),
+		mcp.WithString("page_token",
+			mcp.Description("Page token from previous response to continue pagination"),
+		),
+		mcp.WithString("order_by",
+			mcp.Description("Sort order: createdTime, folder, modifiedTime, name, name_natural, quotaBytesUsed (append ' desc' for descending; default 'folder,name')"),
+		),
+		mcp.WithString("drive_id",
+			mcp.Description("The shared drive containing the folder, if any"),
+		),
+		withDriveQuery(true),
+		withFetchAll(),
+	)
+}
+
+// ListFolderHandler handles drive_list_folder tool calls.
+func (d *DriveTools) ListFolderHandler(ctx context.Context, request mcp.CallToolRequest, args DriveListFolderRequest) (*mcp.CallToolResult, error) {
+	folderID := args.FolderID
+	if folderID == "" {
+		folderID = "root"
+	}
+	if err := validateDriveID(folderID, "folder_id"); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	pageSize := args.PageSize
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+	orderBy := args.OrderBy
+	if orderBy == "" {
+		orderBy = "folder,name"
+	}
+
+	q := &driveQuery{}
+	q.in(folderID, "parents").boolean("trashed", false)
+	if err := args.DriveQuery.apply(q, true); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	response, err := d.listFiles(ctx, request, driveListOptions{
+		q:         q.String(),
+		orderBy:   orderBy,
+		pageToken: args.PageToken,
+		pageSize:  pageSize,
+		driveID:   args.DriveID,
+		fetchAll:  args.FetchAll,
+		maxItems:  args.MaxItems,
+	})
+	if err != nil {
+		return mcp.NewToolResultError("failed to list folder: " + err.Error()), nil
+	}
+
+	data, err := types.MarshalResponse(response)
+	if err != nil {
+		return mcp.NewToolResultError("failed to marshal response: " + err.Error()), nil
+	}
+	return mcp.NewToolResultText(data), nil
+}
+
+// GetMetadataTool returns the tool definition for getting a file's metadata.
+func (d *DriveTools) GetMetadataTool() mcp.Tool {
+	return mcp.NewTool("drive_get_metadata",
+		mcp.WithDescription(`Retrieves metadata for a single Google Drive file of any type.
+
+Returns the file's name, MIME type, size, owners, parents, created and modified times, description, link, shortcut target, and sharing details (who shared it, and its permissions if you can see them).`),
+		mcp.WithString("file_id",
+			mcp.Required(),
+			mcp.Description("The file ID (from the URL or drive_search results)"),
+		),
+	)
+}
+
+// GetMetadataHandler handles drive_get_metadata tool calls.
+func (d *DriveTools) GetMetadataHandler(ctx context.Context, request mcp.CallToolRequest, args DriveGetMetadataRequest) (*mcp.CallToolResult, error) {
+	if args.FileID == "" {
+		return mcp.NewToolResultError("file_id is required"), nil
+	}
+
+	f, err := d.driveService.Files.Get(args.FileID).
+		Context(ctx).
+		Fields(driveMetadataFields).
+		SupportsAllDrives(true).
+		Do()
+	if err != nil {
+		return mcp.NewToolResultError("failed to get file metadata: " + err.Error()), nil
+	}
+	if err := d.checkFilePolicy(ctx, f); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	response := DriveFileMetadata{
+		DriveFile:   convertDriveFile(f),
+		Description: f.Description,
+		CreatedTime: f.CreatedTime,
+		Trashed:     f.Trashed,
+		Starred:     f.Starred,
+	}
+	if f.LastModifyingUser != nil {
+		response.LastModifiedBy = formatDriveUser(f.LastModifyingUser)
+	}
+	if f.SharingUser != nil {
+		response.SharedBy = formatDriveUser(f.SharingUser)
+	}
+	for _, p := range f.Permissions {
+		response.Permissions = append(response.Permissions, DrivePermission{
+			Type:   p.Type,
+			Role:   p.Role,
+			Email:  p.EmailAddress,
+			Domain: p.Domain,
+			Name:   p.DisplayName,
+		})
+	}
+
+	data, err := types.MarshalResponse(response)
+	if err != nil {
+		return mcp.NewToolResultError("failed to marshal response: " + err.Error()), nil
+	}
+	return mcp.NewToolResultText(data), nil
+}
+
+// driveListOptions are the parameters of a Drive files.list request.
+type driveListOptions struct {
+	q, orderBy, pageToken string
+	pageSize              int
+	driveID, corpora      string
+	fetchAll              bool
+	maxItems              int
+}
+
+// listFiles runs a Drive files query and converts the results. If fetchAll is set, it follows
+// page tokens until maxItems results have been collected.
+func (d *DriveTools) listFiles(ctx context.Context, request mcp.CallToolRequest, opts driveListOptions) (DriveListResponse, error) {
+	corpora := opts.corpora
+	if opts.driveID != "" {
+		if err := validateDriveID(opts.driveID, "drive_id"); err != nil {
+			return DriveListResponse{}, err
+		}
+		if corpora == "" {
+			corpora = "drive"
+		}
+	}
+	if corpora != "" && !driveCorpora[corpora] {
+		return DriveListResponse{}, fmt.Errorf("invalid corpora %q: must be user, drive, domain, or allDrives", corpora)
+	}
+	if corpora == "drive" && opts.driveID == "" {
+		return DriveListResponse{}, errors.New("drive_id is required when corpora is drive")
+	}
+
+	fetch := func(ctx context.Context, pageToken string, pageSize int) ([]DriveFile, string, error) {
+		call := d.driveService.Files.List().
+			Context(ctx).
+			Q(opts.q).
+			PageSize(int64(pageSize)).
+			Fields(googleapi.Field("nextPageToken, files(" + driveFileFields + ")")).
+			SupportsAllDrives(true).
+			IncludeItemsFromAllDrives(true)
+
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		if opts.orderBy != "" {
+			call = call.OrderBy(opts.orderBy)
+		}
+		if corpora != "" {
+			call = call.Corpora(corpora)
+		}
+		if opts.driveID != "" {
+			call = call.DriveId(opts.driveID)
+		}
+
+		fileList, err := call.Do()
+		if err != nil {
+			return nil, "", err
+		}
+
+		files := make([]DriveFile, 0, len(fileList.Files))
+		for _, f := range fileList.Files {
+			// Drop files denied by the access policy
+			if err := d.checkFilePolicy(ctx, f); errors.Is(err, types.ErrPolicyDenied) {
+				continue
+			} else if err != nil {
+				return nil, "", err
+			}
+			files = append(files, convertDriveFile(f))
+		}
+		return files, fileList.NextPageToken, nil
+	}
+
+	if !opts.fetchAll {
+		files, next, err := fetch(ctx, opts.pageToken, opts.pageSize)
+		if err != nil {
+			return DriveListResponse{}, err
+		}
+		return DriveListResponse{Files: files, NextPageToken: next}, nil
+	}
+
+	paged, err := fetchAllPages(ctx, opts.pageToken, opts.pageSize, opts.maxItems, types.NewProgress(ctx, request), fetch)
+	if err != nil {
+		return DriveListResponse{}, err
+	}
+	return DriveListResponse{
+		Files:         paged.Items,
+		NextPageToken: paged.NextPageToken,
+		Truncated:     paged.Truncated,
+	}, nil
+}
+
+// convertDriveFile converts a Drive API file to a DriveFile.
+func convertDriveFile(f *drive.File) DriveFile {
+	file := DriveFile{
+		ID:           f.Id,
+		Name:         f.Name,
+		MimeType:     f.MimeType,
+		Size:         f.Size,
+		Parents:      f.Parents,
+		ModifiedTime: f.ModifiedTime,
+		WebViewLink:  f.WebViewLink,
+		Shared:       f.Shared,
+		DriveID:      f.DriveId,
+	}
+	for _, o := range f.Owners {
+		file.Owners = append(file.Owners, o.EmailAddress)
+	}
+	if f.ShortcutDetails != nil {
+		file.ShortcutTargetID = f.ShortcutDetails.TargetId
+		file.ShortcutTargetMimeType = f.ShortcutDetails.TargetMimeType
+	}
+	return file
+}
+
+// formatDriveUser formats a Drive user as "Name <email>".
+func formatDriveUser(u *drive.User) string {
+	switch {
+	case u.DisplayName != "" && u.EmailAddress != "":
+		return u.DisplayName + " <" + u.EmailAddress + ">"
+	case u.DisplayName != "":
+		return u.DisplayName
+	default:
+		return u.EmailAddress
+	}
+}
+
+// MarshalCompact returns a compact text representation of the file list.
+// Format: one file per line as "id | name | mimeType | modifiedTime", with optional
+// "Next Page Token: <token>" appended if pagination continues.
+func (r DriveListResponse) MarshalCompact() string {
+	var sb strings.Builder
+	for _, f := range r.Files {
+		sb.WriteString(f.ID)
+		sb.WriteString(" | ")
+		sb.WriteString(f.Name)
+		sb.WriteString(" | ")
+		sb.WriteString(f.MimeType)
+		if f.ModifiedTime != "" {
+			sb.WriteString(" | ")
+			sb.WriteString(f.ModifiedTime)
+		}
+		if f.ShortcutTargetID != "" {
+			sb.WriteString(" | -> ")
+			sb.WriteString(f.ShortcutTargetID)
+		}
+		sb.WriteString("\n")
+	}
+	if r.Truncated {
+		sb.WriteString("\n(truncated at max_items)")
+	}
+	if r.NextPageToken != "" {
+		sb.WriteString("\nNext Page Token: ")
+		sb.WriteString(r.NextPageToken)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// MarshalCompact returns a compact text representation of the file metadata.
+func (m DriveFileMetadata) MarshalCompact() string {
+	var sb strings.Builder
+	field := func(name, value string) {
+		if value != "" {
+			sb.WriteString(name)
+			sb.WriteString(": ")
+			sb.WriteString(value)
+			sb.WriteString("\n")
+		}
+	}
+
+	field("ID", m.ID)
+	field("Name", m.Name)
+	field("MIME Type", m.MimeType)
+	if m.Size > 0 {
+		field("Size", strconv.FormatInt(m.Size, 10)+" bytes")
+	}
+	field("Owners", strings.Join(m.Owners, ", "))
+	field("Parents", strings.Join(m.Parents, ", "))
+	field("Drive ID", m.DriveID)
+	field("Created", m.CreatedTime)
+	field("Modified", m.ModifiedTime)
+	field("Last Modified By", m.LastModifiedBy)
+	field("Link", m.WebViewLink)
+	field("Shortcut Target", strings.TrimSpace(m.ShortcutTargetID+" "+m.ShortcutTargetMimeType))
+	field("Shared", strconv.FormatBool(m.Shared))
+	field("Shared By", m.SharedBy)
+	if m.Trashed {
+		field("Trashed", "true")
+	}
+	if m.Starred {
+		field("Starred", "true")
+	}
+	field("Description", m.Description)
+	if len(m.Permissions) > 0 {
+		sb.WriteString("Permissions:\n")
+		for _, p := range m.Permissions {
+			who := p.Email
+			if who == "" {
+				who = p.Domain
+			}
+			if who == "" {
+				who = p.Type
+			}
+			sb.WriteString("  - ")
+			sb.WriteString(who)
+			sb.WriteString(" (")
+			sb.WriteString(p.Type)
+			sb.WriteString(", ")
+			sb.WriteString(p.Role)
+			sb.WriteString(")\n")
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// Size limits for drive_get_content.
+const (
+	defaultMaxDownloadBytes = 10 << 20 // 10MB
+	maxDownloadBytesLimit   = 50 << 20 // 50MB
+	defaultMaxContentChars  = 100000
+	maxContentCharsLimit    = 1000000
+)
+
+// driveContentFields are the Drive file fields needed to read a file's content.
+const driveContentFields = "id, name, mimeType, size, shortcutDetails(targetId, targetMimeType), " + docsPolicyFields
+
+// googleExportTypes maps Google Workspace file types to the text format they are exported as.
+var googleExportTypes = map[string]string{
+	docsMimeType: "text/plain",
+	"application/vnd.google-apps.spreadsheet":  "text/csv",
+	"application/vnd.google-apps.presentation": "text/plain",
+}
+
+// DriveGetContentRequest contains arguments for reading a file's text.
+type DriveGetContentRequest struct {
+	FileID   string `json:"file_id"`
+	Pages    string `json:"pages"`     // Page or slide range for PDFs and PowerPoint files, e.g. "1-3,5"
+	Offset   int    `json:"offset"`    // Character offset to start from
+	MaxChars int    `json:"max_chars"` // Maximum characters to return
+	MaxBytes int    `json:"max_bytes"` // Maximum file size to download
+}
+
+// DriveGetContentResponse contains the extracted text of a file.
+type DriveGetContentResponse struct {
+	FileID     string   `json:"fileId"`
+	Name       string   `json:"name"`
+	MimeType   string   `json:"mimeType"`
+	Pages      int      `json:"pages,omitempty"` // Total pages or slides, for paged formats
+	TotalChars int      `json:"totalChars"`      // Characters in the selected pages
+	Offset     int      `json:"offset"`
+	NextOffset int      `json:"nextOffset,omitempty"` // Offset to continue from when truncated
+	Truncated  bool     `json:"truncated,omitempty"`
+	Content    string   `json:"content"`
+	Warnings   []string `json:"warnings,omitempty"` // Untrusted content findings (MCP_HARDEN_UNTRUSTED)
+}
+
+// GetContentTool returns the tool definition for reading a file's text.
+func (d *DriveTools) GetContentTool() mcp.Tool {
+	return mcp.NewTool("drive_get_content",
+		mcp.WithDescription(`Reads the text of a Google Drive file.
+
+Supports PDFs, Word (.docx), PowerPoint (.pptx), and Excel (.xlsx) files, plain text formats (text, Markdown, CSV, JSON, etc.), and Google Docs, Sheets (first sheet, as CSV), and Slides. Shortcuts are followed. For Google Docs, docs_get_content returns richer Markdown.
+
+PDF pages and slides are separated by "--- Page N ---" or "--- Slide N ---" markers; use pages to select a range. Long content is split with offset and max_chars: when truncated, call again with offset set to nextOffset.`),
+		mcp.WithString("file_id",
+			mcp.Required(),
+			mcp.Description("The file ID (from the URL or drive_search results)"),
+		),
+		mcp.WithString("pages",
+			mcp.Description(`Pages (PDF) or slides (PowerPoint) to return, e.g. "1-3,5" or "10-"`),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Character offset to start from (default 0)"),
+			mcp.Min(0),
+		),
+		mcp.WithNumber("max_chars",
+			mcp.Description(fmt.Sprintf("Maximum characters to return (default %d, max %d)", defaultMaxContentChars, maxContentCharsLimit)),
+			mcp.Min(1),
+			mcp.Max(maxContentCharsLimit),
+		),
+		mcp.WithNumber("max_bytes",
+			mcp.Description(fmt.Sprintf("Refuse files larger than this many bytes (default %d, max %d)", defaultMaxDownloadBytes, maxDownloadBytesLimit)),
+			mcp.Min(1),
+			mcp.Max(maxDownloadBytesLimit),
+		),
+	)
+}
+
+// GetContentHandler handles drive_get_content tool calls.
+func (d *DriveTools) GetContentHandler(ctx context.Context, request mcp.CallToolRequest, args DriveGetContentRequest) (*mcp.CallToolResult, error) {
+	if args.FileID == "" {
+		return mcp.NewToolResultError("file_id is required"), nil
+	}
+	maxBytes := args.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxDownloadBytes
+	}
+	maxBytes = min(maxBytes, maxDownloadBytesLimit)
+	maxChars := args.MaxChars
+	if maxChars <= 0 {
+		maxChars = defaultMaxContentChars
+	}
+	maxChars = min(maxChars, maxContentCharsLimit)
+
+	f, err := d.driveService.Files.Get(args.FileID).
+		Context(ctx).
+		Fields(driveContentFields).
+		SupportsAllDrives(true).
+		Do()
+	if err != nil {
+		return mcp.NewToolResultError("failed to get file metadata: " + err.Error()), nil
+	}
+	if f.ShortcutDetails != nil && f.ShortcutDetails.TargetId != "" {
+		f, err = d.driveService.Files.Get(f.ShortcutDetails.TargetId).
+			Context(ctx).
+			Fields(driveContentFields).
+			SupportsAllDrives(true).
+			Do()
+		if err != nil {
+			return mcp.NewToolResultError("failed to get shortcut target metadata: " + err.Error()), nil
+		}
+	}
+	if err := d.checkFilePolicy(ctx, f); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	extracted, pageLabel, err := d.extractFile(ctx, f, maxBytes)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	response := DriveGetContentResponse{
+		FileID:   f.Id,
+		Name:     f.Name,
+		MimeType: f.MimeType,
+		Pages:    len(extracted.pages),
+		Offset:   args.Offset,
+	}
+
+	text := extracted.text
+	if extracted.pages != nil {
+		var selected pageSet
+		if args.Pages != "" {
+			selected, err = parsePageRange(args.Pages, len(extracted.pages))
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+		text = joinPages(extracted.pages, selected, pageLabel)
+	} else if args.Pages != "" {
+		return mcp.NewToolResultError("pages is only supported for PDF and PowerPoint files"), nil
+	}
+
+	runes := []rune(text)
+	response.TotalChars = len(runes)
+	if args.Offset > len(runes) {
+		return mcp.NewToolResultError(fmt.Sprintf("offset %d is past the end of the content (%d characters)", args.Offset, len(runes))), nil
+	}
+	end := min(max(args.Offset, 0)+maxChars, len(runes))
+	response.Content = string(runes[max(args.Offset, 0):end])
+	if end < len(runes) {
+		response.Truncated = true
+		response.NextOffset = end
+	}
+
+	response.Content = wrapUntrusted("drive:"+f.Id, response.Content, &response.Warnings)
+
+	data, err := types.MarshalResponse(response)
+	if err != nil {
+		return mcp.NewToolResultError("failed to marshal response: " + err.Error()), nil
+	}
+	return mcp.NewToolResultText(data), nil
+}
+
+// extractFile downloads or exports a file and extracts its text. It also returns the
+// label used for page markers of paged formats.
+func (d *DriveTools) extractFile(ctx context.Context, f *drive.File, maxBytes int) (extractedText, string, error) {
+	if exportType, ok := googleExportTypes[f.MimeType]; ok {
+		resp, err := d.driveService.Files.Export(f.Id, exportType).Context(ctx).Download()
+		if err != nil {
+			return extractedText{}, "", fmt.Errorf("failed to export file: %w", err)
+		}
+		data, err := readLimited(resp.Body, maxBytes)
+		resp.Body.Close()
+		if err != nil {
+			return extractedText{}, "", err
+		}
+		result, err := decodeText(data)
+		return result, "", err
+	}
+
+	var extract func([]byte) (extractedText, error)
+	label := ""
+	switch {
+	case f.MimeType == "application/pdf":
+		extract, label = extractPDF, "Page"
+	case f.MimeType == pptxMimeType:
+		extract, label = extractPptx, "Slide"
+	case f.MimeType == docxMimeType:
+		extract = extractDocx
+	case f.MimeType == xlsxMimeType:
+		extract = extractXlsx
+	case isTextMimeType(f.MimeType):
+		extract = decodeText
+	default:
+		return extractedText{}, "", fmt.Errorf("unsupported file type %s", f.MimeType)
+	}
+
+	if f.Size > int64(maxBytes) {
+		return extractedText{}, "", fmt.Errorf("file is %d bytes, larger than max_bytes (%d)", f.Size, maxBytes)
+	}
+	resp, err := d.driveService.Files.Get(f.Id).Context(ctx).SupportsAllDrives(true).Download()
+	if err != nil {
+		return extractedText{}, "", fmt.Errorf("failed to download file: %w", err)
+	}
+	data, err := readLimited(resp.Body, maxBytes)
+	resp.Body.Close()
+	if err != nil {
+		return extractedText{}, "", err
+	}
+	result, err := extract(data)
+	return result, label, err
+}
+
+// readLimited reads up to maxBytes from r, failing if there is more.
+func readLimited(r io.Reader, maxBytes int) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, int64(maxBytes)+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if len(data) > maxBytes {
+		return nil, fmt.Errorf("file is larger than max_bytes (%d)", maxBytes)
+	}
+	return data, nil
+}
+
+// MarshalCompact returns a compact text representation of the file content.
+func (r DriveGetContentResponse) MarshalCompact() string {
+	var sb strings.Builder
+	sb.WriteString("=== File: ")
+	sb.WriteString(r.Name)
+	sb.WriteString(" ===\nID: ")
+	sb.WriteString(r.FileID)
+	sb.WriteString("\nType: ")
+	sb.WriteString(r.MimeType)
+	if r.Pages > 0 {
+		fmt.Fprintf(&sb, "\nPages: %d", r.Pages)
+	}
+	fmt.Fprintf(&sb, "\nCharacters: %d", r.TotalChars)
+	if r.Truncated {
+		fmt.Fprintf(&sb, " (truncated; continue with offset %d)", r.NextOffset)
+	}
+	sb.WriteString("\n")
+	for _, w := range r.Warnings {
+		sb.WriteString("Warning: ")
+		sb.WriteString(w)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(r.Content)
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,230 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// docsMimeType is the MIME type of Google Docs.
+const docsMimeType = "application/vnd.google-apps.document"
+
+// driveIDRe matches Drive file, folder, and shared drive IDs.
+var driveIDRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// driveQuery builds a Drive files.list query (the q parameter). Terms are joined
+// with "and"; values are always quoted and escaped, so user input can't change
+// the structure of the query.
+type driveQuery struct {
+	terms []string
+}
+
+// driveQuote quotes a string value for a Drive query, escaping backslashes and single quotes.
+func driveQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "'", `\'`)
+	return "'" + s + "'"
+}
+
+// String returns the query string.
+func (q *driveQuery) String() string {
+	return strings.Join(q.terms, " and ")
+}
+
+// contains adds "field contains 'value'" (field is name or fullText).
+func (q *driveQuery) contains(field, value string) *driveQuery {
+	q.terms = append(q.terms, field+" contains "+driveQuote(value))
+	return q
+}
+
+// equals adds "field = 'value'".
+func (q *driveQuery) equals(field, value string) *driveQuery {
+	q.terms = append(q.terms, field+" = "+driveQuote(value))
+	return q
+}
+
+// boolean adds "field = true" or "field = false".
+func (q *driveQuery) boolean(field string, value bool) *driveQuery {
+	q.terms = append(q.terms, fmt.Sprintf("%s = %t", field, value))
+	return q
+}
+
+// flag adds a term that takes no value, such as sharedWithMe.
+func (q *driveQuery) flag(field string) *driveQuery {
+	q.terms = append(q.terms, field)
+	return q
+}
+
+// in adds "'value' in field" (field is parents, owners, writers, or readers).
+func (q *driveQuery) in(value, field string) *driveQuery {
+	q.terms = append(q.terms, driveQuote(value)+" in "+field)
+	return q
+}
+
+// hasProperty adds a match on a custom file property.
+func (q *driveQuery) hasProperty(key, value string) *driveQuery {
+	q.terms = append(q.terms, fmt.Sprintf("properties has { key=%s and value=%s }", driveQuote(key), driveQuote(value)))
+	return q
+}
+
+// or adds the disjunction of subqueries. Empty subqueries are skipped.
+func (q *driveQuery) or(subqueries ...*driveQuery) *driveQuery {
+	var parts []string
+	for _, sub := range subqueries {
+		if len(sub.terms) > 0 {
+			parts = append(parts, "("+sub.String()+")")
+		}
+	}
+	switch len(parts) {
+	case 0:
+	case 1:
+		q.terms = append(q.terms, parts[0])
+	default:
+		q.terms = append(q.terms, "("+strings.Join(parts, " or ")+")")
+	}
+	return q
+}
+
+// search adds a match on query in file names, content, or both, as selected by mode
+// (name, fullText, or both; default name). Drive can't sort content searches, so
+// orderBy must be empty unless mode is name.
+func (q *driveQuery) search(mode, query, orderBy string) error {
+	if mode == "" {
+		mode = "name"
+	}
+	if mode != "name" && orderBy != "" {
+		return fmt.Errorf("order_by is not supported when searching content; use search_mode=name")
+	}
+	switch mode {
+	case "name":
+		q.contains("name", query)
+	case "fullText":
+		q.contains("fullText", query)
+	case "both":
+		q.or((&driveQuery{}).contains("name", query), (&driveQuery{}).contains("fullText", query))
+	default:
+		return fmt.Errorf("invalid search_mode %q: must be name, fullText, or both", mode)
+	}
+	return nil
+}
+
+// timeAfter adds "field > 'value'" after checking value is an RFC3339 timestamp.
+// arg names the tool argument in error messages. Empty values are ignored.
+func (q *driveQuery) timeAfter(field, value, arg string) error {
+	return q.compareTime(field, ">", value, arg)
+}
+
+// timeBefore adds "field < 'value'" after checking value is an RFC3339 timestamp.
+// arg names the tool argument in error messages. Empty values are ignored.
+func (q *driveQuery) timeBefore(field, value, arg string) error {
+	return q.compareTime(field, "<", value, arg)
+}
+
+// compareTime adds a time comparison after validating the timestamp.
+func (q *driveQuery) compareTime(field, op, value, arg string) error {
+	if value == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: must be an RFC3339 timestamp such as 2025-01-01T00:00:00Z", arg, value)
+	}
+	q.terms = append(q.terms, field+" "+op+" "+driveQuote(t.UTC().Format(time.RFC3339)))
+	return nil
+}
+
+// validateDriveID checks that an ID contains only characters Drive uses in IDs.
+// arg names the tool argument in error messages.
+func validateDriveID(id, arg string) error {
+	if !driveIDRe.MatchString(id) {
+		return fmt.Errorf("invalid %s %q", arg, id)
+	}
+	return nil
+}
+
+// DriveQueryFilter contains structured filters for the drive_query argument.
+type DriveQueryFilter struct {
+	MimeType     string            `json:"mime_type"`      // Exact MIME type
+	Starred      *bool             `json:"starred"`        // Only starred (true) or unstarred (false) files
+	SharedWithMe bool              `json:"shared_with_me"` // Only files shared with the user
+	Parents      []string          `json:"parents"`        // Files in any of these folders
+	Properties   map[string]string `json:"properties"`     // Custom file properties that must all match
+}
+
+// apply adds the filter's terms to q. MIME types are only accepted when allowMimeType
+// is set, since the Docs tools always restrict results to Google Docs.
+func (f *DriveQueryFilter) apply(q *driveQuery, allowMimeType bool) error {
+	if f == nil {
+		return nil
+	}
+	if f.MimeType != "" {
+		if !allowMimeType {
+			return fmt.Errorf("drive_query.mime_type is not supported by this tool")
+		}
+		q.equals("mimeType", f.MimeType)
+	}
+	if f.Starred != nil {
+		q.boolean("starred", *f.Starred)
+	}
+	if f.SharedWithMe {
+		q.flag("sharedWithMe")
+	}
+	if len(f.Parents) > 0 {
+		var parents []*driveQuery
+		for _, id := range f.Parents {
+			if err := validateDriveID(id, "drive_query.parents entry"); err != nil {
+				return err
+			}
+			parents = append(parents, (&driveQuery{}).in(id, "parents"))
+		}
+		q.or(parents...)
+	}
+	// Sort keys so the query is deterministic
+	keys := make([]string, 0, len(f.Properties))
+	for k := range f.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		q.hasProperty(k, f.Properties[k])
+	}
+	return nil
+}
+
+// withDriveQuery adds the structured drive_query argument to a Drive-backed tool.
+func withDriveQuery(allowMimeType bool) mcp.ToolOption {
+	props := map[string]any{
+		"starred": map[string]any{
+			"type":        "boolean",
+			"description": "Only starred (true) or unstarred (false) files",
+		},
+		"shared_with_me": map[string]any{
+			"type":        "boolean",
+			"description": "Only files shared with you",
+		},
+		"parents": map[string]any{
+			"type":        "array",
+			"items":       map[string]any{"type": "string"},
+			"description": "Only files in any of these folder IDs",
+		},
+		"properties": map[string]any{
+			"type":                 "object",
+			"additionalProperties": map[string]any{"type": "string"},
+			"description":          "Custom file properties (key to value) that must all match",
+		},
+	}
+	if allowMimeType {
+		props["mime_type"] = map[string]any{
+			"type":        "string",
+			"description": "Exact MIME type, e.g. application/pdf or application/vnd.google-apps.spreadsheet",
+		}
+	}
+	return mcp.WithObject("drive_query",
+		mcp.Description("Advanced structured Drive filters, combined with the other arguments"),
+		mcp.Properties(props),
+	)
+}
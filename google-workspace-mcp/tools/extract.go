@@ -0,0 +1,457 @@
+package tools
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// MIME types of Office files that can be parsed locally.
+const (
+	docxMimeType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	pptxMimeType = "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+	xlsxMimeType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// maxZipPartBytes caps the decompressed size of a single part of an Office file,
+// guarding against zip bombs.
+const maxZipPartBytes = 64 << 20
+
+// maxXlsxColumns is Excel's column limit (column XFD). Cells with references
+// beyond it are invalid and skipped.
+const maxXlsxColumns = 16384
+
+// extractedText is the text of a file, split into pages when the format has them.
+type extractedText struct {
+	pages []string // PDF pages or slides; nil if the format isn't paged
+	text  string   // Full text when pages is nil
+}
+
+// pageSet is a set of 1-based page numbers selected by a range like "1-3,5".
+type pageSet map[int]bool
+
+// parsePageRange parses a page range such as "1-3,5,8-" against the number of pages.
+// An open-ended range ("8-") runs to the last page.
+func parsePageRange(spec string, total int) (pageSet, error) {
+	pages := pageSet{}
+	for part := range strings.SplitSeq(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(strings.TrimSpace(lo))
+		if err != nil || start < 1 {
+			return nil, fmt.Errorf("invalid page range %q", part)
+		}
+		end := start
+		if isRange {
+			end = total
+			if hi = strings.TrimSpace(hi); hi != "" {
+				if end, err = strconv.Atoi(hi); err != nil || end < start {
+					return nil, fmt.Errorf("invalid page range %q", part)
+				}
+			}
+		}
+		if start > total {
+			return nil, fmt.Errorf("page %d is out of range (the file has %d pages)", start, total)
+		}
+		for p := start; p <= min(end, total); p++ {
+			pages[p] = true
+		}
+	}
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("invalid page range %q", spec)
+	}
+	return pages, nil
+}
+
+// joinPages joins the selected pages (all if selected is nil) under "--- <label> N ---" markers.
+func joinPages(pages []string, selected pageSet, label string) string {
+	var sb strings.Builder
+	for i, text := range pages {
+		if selected != nil && !selected[i+1] {
+			continue
+		}
+		fmt.Fprintf(&sb, "--- %s %d ---\n", label, i+1)
+		sb.WriteString(strings.TrimSpace(text))
+		sb.WriteString("\n\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// extractPDF extracts the text of each page of a PDF.
+func extractPDF(data []byte) (result extractedText, err error) {
+	// The PDF parser panics on some malformed files
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to parse PDF: %v", r)
+		}
+	}()
+
+	r, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return extractedText{}, fmt.Errorf("failed to parse PDF: %w", err)
+	}
+	fonts := map[string]*pdf.Font{}
+	for i := 1; i <= r.NumPage(); i++ {
+		p := r.Page(i)
+		// Cache fonts across pages so their character maps are only parsed once
+		for _, name := range p.Fonts() {
+			if _, ok := fonts[name]; !ok {
+				f := p.Font(name)
+				fonts[name] = &f
+			}
+		}
+		text, err := p.GetPlainText(fonts)
+		if err != nil {
+			return extractedText{}, fmt.Errorf("failed to extract text from page %d: %w", i, err)
+		}
+		result.pages = append(result.pages, text)
+	}
+	return result, nil
+}
+
+// openZip opens an Office file as a zip archive.
+func openZip(data []byte) (*zip.Reader, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Office file: %w", err)
+	}
+	return zr, nil
+}
+
+// readZipPart reads a part of a zip archive, or returns nil if it doesn't exist.
+func readZipPart(zr *zip.Reader, name string) ([]byte, error) {
+	f, err := zr.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxZipPartBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxZipPartBytes {
+		return nil, fmt.Errorf("%s is larger than %d bytes", name, maxZipPartBytes)
+	}
+	return data, nil
+}
+
+// requireZipPart reads a part of a zip archive that must exist.
+func requireZipPart(zr *zip.Reader, name string) ([]byte, error) {
+	data, err := readZipPart(zr, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if data == nil {
+		return nil, fmt.Errorf("invalid Office file: %s is missing", name)
+	}
+	return data, nil
+}
+
+// ooxmlText extracts the text of a WordprocessingML or DrawingML part. Text comes
+// from <t> elements; paragraphs end with a newline, and tabs and breaks are kept.
+func ooxmlText(data []byte) (string, error) {
+	var sb strings.Builder
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	inText := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				sb.WriteString("\t")
+			case "br", "cr":
+				sb.WriteString("\n")
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				sb.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				sb.Write(t)
+			}
+		}
+	}
+	return sb.String(), nil
+}
+
+// extractDocx extracts the text of a Word document.
+func extractDocx(data []byte) (extractedText, error) {
+	zr, err := openZip(data)
+	if err != nil {
+		return extractedText{}, err
+	}
+	part, err := requireZipPart(zr, "word/document.xml")
+	if err != nil {
+		return extractedText{}, err
+	}
+	text, err := ooxmlText(part)
+	if err != nil {
+		return extractedText{}, fmt.Errorf("failed to parse Word document: %w", err)
+	}
+	return extractedText{text: text}, nil
+}
+
+// slidePartRe matches slide parts and captures the slide number.
+var slidePartRe = regexp.MustCompile(`^ppt/slides/slide(\d+)\.xml$`)
+
+// extractPptx extracts the text of each slide of a PowerPoint presentation.
+func extractPptx(data []byte) (extractedText, error) {
+	zr, err := openZip(data)
+	if err != nil {
+		return extractedText{}, err
+	}
+
+	type slide struct {
+		num  int
+		name string
+	}
+	var slides []slide
+	for _, f := range zr.File {
+		if m := slidePartRe.FindStringSubmatch(f.Name); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			slides = append(slides, slide{n, f.Name})
+		}
+	}
+	sort.Slice(slides, func(i, j int) bool { return slides[i].num < slides[j].num })
+
+	var result extractedText
+	for _, s := range slides {
+		part, err := requireZipPart(zr, s.name)
+		if err != nil {
+			return extractedText{}, fmt.Errorf("failed to read slide %d: %w", s.num, err)
+		}
+		text, err := ooxmlText(part)
+		if err != nil {
+			return extractedText{}, fmt.Errorf("failed to parse slide %d: %w", s.num, err)
+		}
+		result.pages = append(result.pages, text)
+	}
+	return result, nil
+}
+
+// xlsxSheet is a worksheet listed in an Excel workbook.
+type xlsxSheet struct {
+	Name string `xml:"name,attr"`
+	RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+}
+
+// xlsxCell is a worksheet cell.
+type xlsxCell struct {
+	Ref    string `xml:"r,attr"`
+	Type   string `xml:"t,attr"`
+	Value  string `xml:"v"`
+	Inline struct {
+		Text []string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"is"`
+}
+
+// extractXlsx extracts each worksheet of an Excel workbook as CSV.
+func extractXlsx(data []byte) (extractedText, error) {
+	zr, err := openZip(data)
+	if err != nil {
+		return extractedText{}, err
+	}
+
+	// Shared strings are referenced by index from cells of type "s"
+	var shared []string
+	if part, err := readZipPart(zr, "xl/sharedStrings.xml"); err != nil {
+		return extractedText{}, fmt.Errorf("failed to read shared strings: %w", err)
+	} else if part != nil {
+		var sst struct {
+			Items []struct {
+				Text []string `xml:"t"`
+				Runs []struct {
+					Text string `xml:"t"`
+				} `xml:"r"`
+			} `xml:"si"`
+		}
+		if err := xml.Unmarshal(part, &sst); err != nil {
+			return extractedText{}, fmt.Errorf("failed to parse shared strings: %w", err)
+		}
+		for _, si := range sst.Items {
+			s := strings.Join(si.Text, "")
+			for _, r := range si.Runs {
+				s += r.Text
+			}
+			shared = append(shared, s)
+		}
+	}
+
+	// Resolve sheet names to their worksheet parts through the workbook relationships
+	var workbook struct {
+		Sheets []xlsxSheet `xml:"sheets>sheet"`
+	}
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	for name, v := range map[string]any{"xl/workbook.xml": &workbook, "xl/_rels/workbook.xml.rels": &rels} {
+		part, err := requireZipPart(zr, name)
+		if err != nil {
+			return extractedText{}, err
+		}
+		if err := xml.Unmarshal(part, v); err != nil {
+			return extractedText{}, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+	}
+	targets := map[string]string{}
+	for _, r := range rels.Relationships {
+		target := strings.TrimPrefix(r.Target, "/")
+		if !strings.HasPrefix(target, "xl/") {
+			target = path.Join("xl", target)
+		}
+		targets[r.ID] = target
+	}
+
+	var sb strings.Builder
+	for _, sheet := range workbook.Sheets {
+		part, err := requireZipPart(zr, targets[sheet.RID])
+		if err != nil {
+			return extractedText{}, err
+		}
+		var ws struct {
+			Rows []struct {
+				Cells []xlsxCell `xml:"c"`
+			} `xml:"sheetData>row"`
+		}
+		if err := xml.Unmarshal(part, &ws); err != nil {
+			return extractedText{}, fmt.Errorf("failed to parse sheet %q: %w", sheet.Name, err)
+		}
+
+		fmt.Fprintf(&sb, "--- Sheet: %s ---\n", sheet.Name)
+		w := csv.NewWriter(&sb)
+		for _, row := range ws.Rows {
+			var record []string
+			for i, c := range row.Cells {
+				// Cells may skip empty columns; place them by their reference
+				col := columnIndex(c.Ref)
+				if col < 0 {
+					col = i
+				}
+				if col >= maxXlsxColumns {
+					continue
+				}
+				for len(record) < col {
+					record = append(record, "")
+				}
+				record = append(record, xlsxCellValue(c, shared))
+			}
+			if err := w.Write(record); err != nil {
+				return extractedText{}, err
+			}
+			// Sparse rows expand to many empty columns, so cap the output as well
+			if sb.Len() > maxZipPartBytes {
+				return extractedText{}, fmt.Errorf("workbook text exceeds %d MB", maxZipPartBytes>>20)
+			}
+		}
+		w.Flush()
+		sb.WriteString("\n")
+	}
+	return extractedText{text: sb.String()}, nil
+}
+
+// xlsxCellValue returns the displayed value of a cell.
+func xlsxCellValue(c xlsxCell, shared []string) string {
+	switch c.Type {
+	case "s":
+		i, err := strconv.Atoi(c.Value)
+		if err != nil || i < 0 || i >= len(shared) {
+			return ""
+		}
+		return shared[i]
+	case "inlineStr":
+		s := strings.Join(c.Inline.Text, "")
+		for _, r := range c.Inline.Runs {
+			s += r.Text
+		}
+		return s
+	case "b":
+		if c.Value == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	default:
+		return c.Value
+	}
+}
+
+// columnIndex converts the column letters of a cell reference like "C7" to a
+// 0-based index. It returns -1 if the reference has no column letters, and
+// maxXlsxColumns for columns beyond Excel's limit.
+func columnIndex(ref string) int {
+	col := 0
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		if col > maxXlsxColumns {
+			return maxXlsxColumns
+		}
+		n++
+	}
+	if n == 0 {
+		return -1
+	}
+	return col - 1
+}
+
+// isTextMimeType reports whether a MIME type can be returned as text directly.
+func isTextMimeType(mimeType string) bool {
+	if strings.HasPrefix(mimeType, "text/") {
+		return true
+	}
+	switch mimeType {
+	case "application/json", "application/xml", "application/x-yaml", "application/yaml",
+		"application/javascript", "application/x-sh", "application/sql", "application/toml":
+		return true
+	}
+	return false
+}
+
+// decodeText returns data as text, rejecting binary content.
+func decodeText(data []byte) (extractedText, error) {
+	data = bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF")) // UTF-8 byte order mark
+	if !utf8.Valid(data) {
+		return extractedText{}, errors.New("file is not valid UTF-8 text")
+	}
+	return extractedText{text: string(data)}, nil
+}
@@ -17,6 +17,8 @@ type GmailSearchRequest struct {
 	Query     string `json:"query"`      // Gmail search query using standard operators
 	PageSize  int    `json:"page_size"`  // Maximum results to return (default 10, max 100)
 	PageToken string `json:"page_token"` // Pagination token from previous response
+	FetchAll  bool   `json:"fetch_all"`  // Follow page tokens and merge all pages
+	MaxItems  int    `json:"max_items"`  // Cap on total results when FetchAll is set
 }
 
 // GmailGetMessageRequest contains arguments for getting a Gmail message.
@@ -71,6 +73,7 @@ Returns message and thread IDs for use with gmail_get_message and gmail_get_thre
 		mcp.WithString("page_token",
 			mcp.Description("Page token for retrieving subsequent pages of results"),
 		),
+		withFetchAll(),
 	)
 }
 
@@ -86,6 +89,7 @@ type GmailSearchResult struct {
 type GmailSearchResponse struct {
 	Results       []GmailSearchResult `json:"results"`
 	NextPageToken string              `json:"next_page_token,omitempty"`
+	Truncated     bool                `json:"truncated,omitempty"` // fetch_all stopped at max_items
 }
 
 // SearchHandler handles gmail_search tool calls.
@@ -97,36 +101,71 @@ func (g *GmailTools) SearchHandler(ctx context.Context, request mcp.CallToolRequ
 	pageSize := args.PageSize
 	if pageSize <= 0 {
 		pageSize = 10
+		// Use larger pages when following page tokens to save round trips
+		if args.FetchAll {
+			pageSize = 100
+		}
 	}
 	if pageSize > 100 {
 		pageSize = 100
 	}
 
-	call := g.gmailService.Users.Messages.List("me").
-		Context(ctx).
-		Q(args.Query).
-		MaxResults(int64(pageSize))
-
-	if args.PageToken != "" {
-		call = call.PageToken(args.PageToken)
+	query := args.Query
+	if exclude := denyLabelQuery(); exclude != "" {
+		query = "(" + query + ") " + exclude
 	}
 
-	msgList, err := call.Do()
-	if err != nil {
-		return mcp.NewToolResultError("failed to search messages: " + err.Error()), nil
-	}
+	fetch := func(ctx context.Context, pageToken string, pageSize int) ([]GmailSearchResult, string, error) {
+		call := g.gmailService.Users.Messages.List("me").
+			Context(ctx).
+			Q(query).
+			MaxResults(int64(pageSize))
+
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+
+		msgList, err := call.Do()
+		if err != nil {
+			return nil, "", err
+		}
+
+		results := make([]GmailSearchResult, 0, len(msgList.Messages))
+		for _, msg := range msgList.Messages {
+			results = append(results, GmailSearchResult{
+				MessageID: msg.Id,
+				ThreadID:  msg.ThreadId,
+			})
+		}
 
-	results := make([]GmailSearchResult, 0, len(msgList.Messages))
-	for _, msg := range msgList.Messages {
-		results = append(results, GmailSearchResult{
-			MessageID: msg.Id,
-			ThreadID:  msg.ThreadId,
-		})
+		// Drop messages denied by the access policy
+		results, err = g.filterSearchResults(ctx, results)
+		if err != nil {
+			return nil, "", err
+		}
+		return results, msgList.NextPageToken, nil
 	}
 
-	response := GmailSearchResponse{
-		Results:       results,
-		NextPageToken: msgList.NextPageToken,
+	var response GmailSearchResponse
+	if args.FetchAll {
+		paged, err := fetchAllPages(ctx, args.PageToken, pageSize, args.MaxItems, types.NewProgress(ctx, request), fetch)
+		if err != nil {
+			return mcp.NewToolResultError("failed to search messages: " + err.Error()), nil
+		}
+		response = GmailSearchResponse{
+			Results:       paged.Items,
+			NextPageToken: paged.NextPageToken,
+			Truncated:     paged.Truncated,
+		}
+	} else {
+		results, next, err := fetch(ctx, args.PageToken, pageSize)
+		if err != nil {
+			return mcp.NewToolResultError("failed to search messages: " + err.Error()), nil
+		}
+		response = GmailSearchResponse{
+			Results:       results,
+			NextPageToken: next,
+		}
 	}
 
 	data, err := types.MarshalResponse(response)
@@ -171,6 +210,7 @@ type GmailGetMessageResponse struct {
 	Date        string                `json:"date,omitempty"`
 	Body        string                `json:"body,omitempty"`
 	Attachments []GmailAttachmentInfo `json:"attachments,omitempty"`
+	Warnings    []string              `json:"warnings,omitempty"` // Untrusted content findings (MCP_HARDEN_UNTRUSTED)
 }
 
 // GetMessageHandler handles gmail_get_message tool calls.
@@ -187,6 +227,10 @@ func (g *GmailTools) GetMessageHandler(ctx context.Context, request mcp.CallTool
 		return mcp.NewToolResultError("failed to get message: " + err.Error()), nil
 	}
 
+	if err := g.checkPolicy(ctx, msg); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	response := extractMessage(msg)
 
 	data, err := types.MarshalResponse(response)
@@ -221,14 +265,28 @@ func extractMessage(msg *gmail.Message) GmailGetMessageResponse {
 		}
 
 		// Extract body and attachments
-		response.Body, response.Attachments = extractBodyAndAttachments(msg.Payload)
+		var htmlBody string
+		response.Body, htmlBody, response.Attachments = extractBodyAndAttachments(msg.Payload)
+
+		// Sanitize attacker-controlled content when hardening is enabled
+		if types.GlobalHardenUntrusted {
+			response.Subject = sanitizeUntrustedField("subject", response.Subject, &response.Warnings)
+			response.From = sanitizeUntrustedField("from", response.From, &response.Warnings)
+			if htmlBody != "" {
+				for _, text := range findHiddenHTMLText(htmlBody) {
+					response.Warnings = append(response.Warnings, "hidden HTML text: "+truncateRunes(text, 200))
+				}
+			}
+			response.Body = wrapUntrusted("gmail:"+msg.Id, response.Body, &response.Warnings)
+		}
 	}
 
 	return response
 }
 
 // extractBodyAndAttachments extracts the body text and attachment info from a message payload.
-func extractBodyAndAttachments(payload *gmail.MessagePart) (string, []GmailAttachmentInfo) {
+// It also returns the raw HTML body, if any, for callers that need to inspect markup.
+func extractBodyAndAttachments(payload *gmail.MessagePart) (string, string, []GmailAttachmentInfo) {
 	var plainText, htmlText string
 	var attachments []GmailAttachmentInfo
 
@@ -278,7 +336,7 @@ func extractBodyAndAttachments(payload *gmail.MessagePart) (string, []GmailAttac
 		body = stripHTMLTags(htmlText)
 	}
 
-	return body, attachments
+	return body, htmlText, attachments
 }
 
 // stripHTMLTags removes HTML tags from a string (simple implementation).
@@ -324,9 +382,10 @@ Returns all messages in the thread in chronological order, each with:
 
 // GmailGetThreadResponse represents a complete thread.
 type GmailGetThreadResponse struct {
-	ThreadID string                 `json:"thread_id"`
-	Subject  string                 `json:"subject,omitempty"`
-	Messages []GmailGetMessageResponse `json:"messages"`
+	ThreadID         string                    `json:"thread_id"`
+	Subject          string                    `json:"subject,omitempty"`
+	Messages         []GmailGetMessageResponse `json:"messages"`
+	WithheldMessages int                       `json:"withheld_messages,omitempty"` // Messages hidden by access policy
 }
 
 // GetThreadHandler handles gmail_get_thread tool calls.
@@ -348,7 +407,22 @@ func (g *GmailTools) GetThreadHandler(ctx context.Context, request mcp.CallToolR
 		Messages: make([]GmailGetMessageResponse, 0, len(thread.Messages)),
 	}
 
-	for _, msg := range thread.Messages {
+	labelNames, err := g.gmailLabelNames(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	progress := types.NewProgress(ctx, request)
+	total := float64(len(thread.Messages))
+	for i, msg := range thread.Messages {
+		if err := ctx.Err(); err != nil {
+			return mcp.NewToolResultError("failed to get thread: " + err.Error()), nil
+		}
+		// Withhold individual messages denied by the access policy
+		if err := checkMessagePolicy(msg, labelNames); err != nil {
+			response.WithheldMessages++
+			continue
+		}
 		msgResponse := extractMessage(msg)
 		response.Messages = append(response.Messages, msgResponse)
 
@@ -356,6 +430,10 @@ func (g *GmailTools) GetThreadHandler(ctx context.Context, request mcp.CallToolR
 		if response.Subject == "" && msgResponse.Subject != "" {
 			response.Subject = msgResponse.Subject
 		}
+		progress.Report(float64(i+1), total, "processed message "+msg.Id)
+	}
+	if len(response.Messages) == 0 && response.WithheldMessages > 0 {
+		return mcp.NewToolResultError(fmt.Sprintf("%s: all messages in thread %s are restricted", types.ErrPolicyDenied, thread.Id)), nil
 	}
 
 	data, err := types.MarshalResponse(response)
@@ -464,6 +542,10 @@ func (g *GmailTools) GetAttachmentHandler(ctx context.Context, request mcp.CallT
 		return mcp.NewToolResultError("failed to get message: " + err.Error()), nil
 	}
 
+	if err := g.checkPolicy(ctx, msg); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	// Find the attachment metadata
 	var filename, mimeType string
 	var findAttachment func(part *gmail.MessagePart)
@@ -519,6 +601,9 @@ func (g GmailSearchResponse) MarshalCompact() string {
 			sb.WriteString("\n")
 		}
 	}
+	if g.Truncated {
+		sb.WriteString("\n(truncated at max_items)")
+	}
 	if g.NextPageToken != "" {
 		sb.WriteString("\nNext Page Token: ")
 		sb.WriteString(g.NextPageToken)
@@ -561,6 +646,14 @@ func (g GmailGetMessageResponse) MarshalCompact() string {
 		sb.WriteString(g.Body)
 	}
 
+	if len(g.Warnings) > 0 {
+		sb.WriteString("\n\nWarnings:")
+		for _, w := range g.Warnings {
+			sb.WriteString("\n  ")
+			sb.WriteString(w)
+		}
+	}
+
 	if len(g.Attachments) > 0 {
 		sb.WriteString("\n\nAttachments:")
 		for _, att := range g.Attachments {
@@ -598,6 +691,10 @@ func (g GmailGetThreadResponse) MarshalCompact() string {
 		sb.WriteString(msg.MarshalCompact())
 	}
 
+	if g.WithheldMessages > 0 {
+		sb.WriteString(fmt.Sprintf("\n\n(%d messages withheld by access policy)", g.WithheldMessages))
+	}
+
 	return sb.String()
 }
 
@@ -666,3 +763,12 @@ func formatSize(bytes int64) string {
 	}
 	return fmt.Sprintf("%.1f%cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
+
+// truncateRunes shortens s to at most n runes, appending "..." if truncated.
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-3]) + "..."
+}
@@ -0,0 +1,594 @@
+package tools
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+	"google.golang.org/api/docs/v1"
+)
+
+// Markdown is converted to Docs batchUpdate requests in two steps: the Markdown AST
+// is flattened into blocks of styled paragraphs and tables, and the blocks are then
+// inserted at the start of the document in reverse order. Inserting everything at
+// index 1 means each block's ranges can be computed from its own text alone, since
+// the blocks inserted before it all end up after it.
+
+// codeFontFamily is the font used for code. It is one of monospaceFonts, so code
+// survives a round trip through docs_get_content.
+const codeFontFamily = "Courier New"
+
+// quoteIndentPoints is the indentation of each blockquote level.
+const quoteIndentPoints = 36
+
+// lineBreak is the character Docs uses for a line break within a paragraph.
+const lineBreak = "\u000b"
+
+// brTagRe matches an HTML line break tag.
+var brTagRe = regexp.MustCompile(`(?i)^<br\s*/?>$`)
+
+// Bullet presets for lists.
+const (
+	bulletPresetUnordered = "BULLET_DISC_CIRCLE_SQUARE"
+	bulletPresetOrdered   = "NUMBERED_DECIMAL_ALPHA_ROMAN"
+	bulletPresetChecklist = "BULLET_CHECKBOX"
+)
+
+// mdStyle is the inline style of a run of text.
+type mdStyle struct {
+	bold, italic, strike, code bool
+	link                       string
+}
+
+// mdRun is a run of text with a single style.
+type mdRun struct {
+	text  string
+	style mdStyle
+}
+
+// mdParagraph is a paragraph of styled runs.
+type mdParagraph struct {
+	runs    []mdRun
+	heading int // Heading level 1-6, or 0
+	quote   int // Blockquote nesting depth
+	level   int // List nesting level
+}
+
+// mdBlock is a unit of insertion: a group of paragraphs (a list, when bullets is
+// set), a table, or a section break.
+type mdBlock struct {
+	paragraphs   []mdParagraph
+	bullets      string      // Bullet preset, for lists
+	table        [][][]mdRun // Rows of cells, for tables
+	header       bool        // The first table row is a header
+	sectionBreak bool
+}
+
+// mdConverter converts a goldmark AST to blocks.
+type mdConverter struct {
+	source []byte
+	blocks []mdBlock
+	notes  []string // Markdown features that couldn't be reproduced exactly
+}
+
+// markdownToDocsRequests parses Markdown and returns the batchUpdate requests that
+// recreate it in an empty document, along with notes about content that couldn't be
+// converted exactly. It also returns the text of the first heading, if any.
+func markdownToDocsRequests(markdown string) (requests []*docs.Request, firstHeading string, notes []string) {
+	source := []byte(markdown)
+	md := goldmark.New(goldmark.WithExtensions(extension.GFM))
+	doc := md.Parser().Parse(text.NewReader(source))
+
+	c := &mdConverter{source: source}
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		c.block(n, 0)
+	}
+
+	for _, b := range c.blocks {
+		for _, p := range b.paragraphs {
+			if p.heading > 0 && firstHeading == "" {
+				firstHeading = strings.TrimSpace(runsText(p.runs))
+			}
+		}
+	}
+	return c.requests(), firstHeading, dedupe(c.notes)
+}
+
+// dedupe returns the distinct strings in order of first appearance.
+func dedupe(items []string) []string {
+	seen := map[string]bool{}
+	var result []string
+	for _, s := range items {
+		if !seen[s] {
+			seen[s] = true
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// block converts a block node. quote is the enclosing blockquote depth.
+func (c *mdConverter) block(n ast.Node, quote int) {
+	switch n := n.(type) {
+	case *ast.Heading:
+		c.addParagraph(mdParagraph{runs: c.inlines(n, mdStyle{}), heading: n.Level, quote: quote})
+	case *ast.Paragraph, *ast.TextBlock:
+		c.addParagraph(mdParagraph{runs: c.inlines(n, mdStyle{}), quote: quote})
+	case *ast.ThematicBreak:
+		c.blocks = append(c.blocks, mdBlock{sectionBreak: true})
+	case *ast.CodeBlock, *ast.FencedCodeBlock:
+		c.addParagraph(mdParagraph{runs: []mdRun{c.codeRun(n)}, quote: quote})
+	case *ast.HTMLBlock:
+		c.notes = append(c.notes, "HTML blocks were inserted as plain text")
+		c.addParagraph(mdParagraph{runs: []mdRun{{text: strings.TrimRight(c.lines(n), "\n")}}, quote: quote})
+	case *ast.Blockquote:
+		c.notes = append(c.notes, "block quotes were inserted as indented paragraphs")
+		for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+			c.block(child, quote+1)
+		}
+	case *ast.List:
+		b := mdBlock{bullets: bulletPresetUnordered}
+		if n.IsOrdered() {
+			b.bullets = bulletPresetOrdered
+		}
+		c.list(&b, n, quote, 0)
+		c.blocks = append(c.blocks, b)
+	case *east.Table:
+		c.table(n)
+	default:
+		for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+			c.block(child, quote)
+		}
+	}
+}
+
+// addParagraph appends a paragraph to the current text block, starting a new block
+// if the last one is a list, table, or section break.
+func (c *mdConverter) addParagraph(p mdParagraph) {
+	if len(c.blocks) == 0 || !c.blocks[len(c.blocks)-1].isText() {
+		c.blocks = append(c.blocks, mdBlock{})
+	}
+	last := &c.blocks[len(c.blocks)-1]
+	last.paragraphs = append(last.paragraphs, p)
+}
+
+// isText reports whether b is a group of plain (non-list) paragraphs.
+func (b mdBlock) isText() bool {
+	return b.bullets == "" && b.table == nil && !b.sectionBreak
+}
+
+// list adds the items of a list, and of lists nested in them, to b. Every item
+// becomes one paragraph; further paragraphs and code blocks in an item are joined
+// to it with line breaks, since they would otherwise become separate list items.
+func (c *mdConverter) list(b *mdBlock, n *ast.List, quote, level int) {
+	for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+		p := mdParagraph{quote: quote, level: level}
+		var nested []*ast.List
+		for child := item.FirstChild(); child != nil; child = child.NextSibling() {
+			var runs []mdRun
+			switch child := child.(type) {
+			case *ast.List:
+				nested = append(nested, child)
+				continue
+			case *ast.CodeBlock, *ast.FencedCodeBlock:
+				runs = []mdRun{c.codeRun(child)}
+			case *ast.Paragraph, *ast.TextBlock:
+				runs = c.inlines(child, mdStyle{})
+			default:
+				runs = []mdRun{{text: strings.TrimRight(c.lines(child), "\n")}}
+			}
+			if box, ok := child.FirstChild().(*east.TaskCheckBox); ok {
+				// Docs has no checked state for checklist items it can set through the
+				// API, so checked items are struck through as Docs shows them
+				if level == 0 && len(b.paragraphs) == 0 {
+					b.bullets = bulletPresetChecklist
+				}
+				if box.IsChecked {
+					for i := range runs {
+						runs[i].style.strike = true
+					}
+				}
+			}
+			if len(p.runs) > 0 {
+				p.runs = append(p.runs, mdRun{text: lineBreak})
+			}
+			p.runs = append(p.runs, runs...)
+		}
+		b.paragraphs = append(b.paragraphs, p)
+		for _, sub := range nested {
+			// Docs applies one bullet preset to every level of a list
+			if sub.IsOrdered() != (b.bullets == bulletPresetOrdered) {
+				c.notes = append(c.notes, "nested lists were given the style of their outermost list")
+			}
+			if level+1 > 8 {
+				c.notes = append(c.notes, "lists nested more than 9 levels deep were flattened")
+			}
+			c.list(b, sub, quote, min(level+1, 8))
+		}
+	}
+}
+
+// table adds a GFM table block.
+func (c *mdConverter) table(n *east.Table) {
+	b := mdBlock{}
+	for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+		var cells [][]mdRun
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			cells = append(cells, c.inlines(cell, mdStyle{}))
+		}
+		if _, ok := row.(*east.TableHeader); ok {
+			// docs_get_content writes an empty header row for tables without one
+			if strings.TrimSpace(runsText(flatten(cells))) == "" {
+				continue
+			}
+			// Pinned header rows are read back as Markdown table headers
+			b.header = true
+		}
+		b.table = append(b.table, cells)
+	}
+	if len(b.table) == 0 {
+		return
+	}
+	c.blocks = append(c.blocks, b)
+}
+
+// flatten joins the runs of several cells.
+func flatten(cells [][]mdRun) []mdRun {
+	var runs []mdRun
+	for _, cell := range cells {
+		runs = append(runs, cell...)
+	}
+	return runs
+}
+
+// runsText returns the text of runs.
+func runsText(runs []mdRun) string {
+	var sb strings.Builder
+	for _, r := range runs {
+		sb.WriteString(r.text)
+	}
+	return sb.String()
+}
+
+// lines returns the raw source lines of a block node.
+func (c *mdConverter) lines(n ast.Node) string {
+	var sb strings.Builder
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		seg := lines.At(i)
+		sb.Write(seg.Value(c.source))
+	}
+	return sb.String()
+}
+
+// codeRun returns a code block as a single monospace run, with line breaks
+// between its lines, which docs_get_content reads back as a code block.
+func (c *mdConverter) codeRun(n ast.Node) mdRun {
+	code := strings.TrimRight(c.lines(n), "\n")
+	return mdRun{text: strings.ReplaceAll(code, "\n", lineBreak), style: mdStyle{code: true}}
+}
+
+// inlines converts the inline children of n, applying style to every run.
+func (c *mdConverter) inlines(n ast.Node, style mdStyle) []mdRun {
+	var runs []mdRun
+	for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+		runs = append(runs, c.inline(child, style)...)
+	}
+	return runs
+}
+
+// inline converts an inline node.
+func (c *mdConverter) inline(n ast.Node, style mdStyle) []mdRun {
+	switch n := n.(type) {
+	case *ast.Text:
+		runs := []mdRun{{text: unescapeMarkdown(n.Segment.Value(c.source)), style: style}}
+		if n.HardLineBreak() {
+			runs = append(runs, mdRun{text: lineBreak, style: style})
+		} else if n.SoftLineBreak() {
+			runs = append(runs, mdRun{text: " ", style: style})
+		}
+		return runs
+	case *ast.String:
+		return []mdRun{{text: string(n.Value), style: style}}
+	case *ast.CodeSpan:
+		style.code = true
+		var sb strings.Builder
+		for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+			if t, ok := child.(*ast.Text); ok {
+				sb.Write(t.Segment.Value(c.source))
+			} else if s, ok := child.(*ast.String); ok {
+				sb.Write(s.Value)
+			}
+		}
+		return []mdRun{{text: sb.String(), style: style}}
+	case *ast.Emphasis:
+		if n.Level >= 2 {
+			style.bold = true
+		} else {
+			style.italic = true
+		}
+		return c.inlines(n, style)
+	case *east.Strikethrough:
+		style.strike = true
+		return c.inlines(n, style)
+	case *ast.Link:
+		style.link = c.linkURL(string(n.Destination))
+		return c.inlines(n, style)
+	case *ast.AutoLink:
+		label := string(n.Label(c.source))
+		style.link = c.linkURL(string(n.URL(c.source)))
+		return []mdRun{{text: label, style: style}}
+	case *ast.Image:
+		c.notes = append(c.notes, "images were inserted as links")
+		style.link = c.linkURL(string(n.Destination))
+		runs := c.inlines(n, style)
+		if len(runs) == 0 {
+			runs = []mdRun{{text: string(n.Destination), style: style}}
+		}
+		return runs
+	case *ast.RawHTML:
+		var sb strings.Builder
+		for i := 0; i < n.Segments.Len(); i++ {
+			seg := n.Segments.At(i)
+			sb.Write(seg.Value(c.source))
+		}
+		if brTagRe.MatchString(sb.String()) {
+			return []mdRun{{text: lineBreak, style: style}}
+		}
+		c.notes = append(c.notes, "inline HTML was inserted as plain text")
+		return []mdRun{{text: sb.String(), style: style}}
+	case *east.TaskCheckBox:
+		// Handled by list
+		return nil
+	default:
+		return c.inlines(n, style)
+	}
+}
+
+// unescapeMarkdown resolves backslash escapes and character references in text.
+func unescapeMarkdown(b []byte) string {
+	return string(util.ResolveEntityNames(util.ResolveNumericReferences(util.UnescapePunctuations(b))))
+}
+
+// linkURL returns dest if Docs can link to it. Docs only accepts absolute URLs, so
+// relative links are dropped and noted.
+func (c *mdConverter) linkURL(dest string) string {
+	u, err := url.Parse(dest)
+	if err != nil || u.Scheme == "" {
+		c.notes = append(c.notes, "relative links were inserted as plain text")
+		return ""
+	}
+	return dest
+}
+
+// utf16Len returns the length of s in UTF-16 code units, the unit of Docs indexes.
+func utf16Len(s string) int64 {
+	return int64(len(utf16.Encode([]rune(s))))
+}
+
+// requests returns the batchUpdate requests for all blocks, last block first.
+func (c *mdConverter) requests() []*docs.Request {
+	var requests []*docs.Request
+	// A new document holds one empty paragraph; tables and section breaks also leave
+	// an empty paragraph at index 1. Text inserted there fills it rather than adding
+	// a newline of its own.
+	fillEmpty := true
+	for i := len(c.blocks) - 1; i >= 0; i-- {
+		b := c.blocks[i]
+		switch {
+		case b.table != nil:
+			requests = append(requests, tableRequests(b)...)
+			fillEmpty = true
+		case b.sectionBreak:
+			requests = append(requests, &docs.Request{InsertSectionBreak: &docs.InsertSectionBreakRequest{
+				Location:    &docs.Location{Index: 1},
+				SectionType: "CONTINUOUS",
+			}})
+			fillEmpty = true
+		default:
+			requests = append(requests, textBlockRequests(b, fillEmpty)...)
+			fillEmpty = false
+		}
+	}
+	return requests
+}
+
+// textBlockRequests inserts a group of paragraphs at index 1 and styles them.
+// Inserted text takes on the style of the text around it, so all text and
+// paragraph styles are reset before the block's own styles are applied. If
+// fillEmpty is set, the last paragraph reuses the empty paragraph at index 1.
+func textBlockRequests(b mdBlock, fillEmpty bool) []*docs.Request {
+	var sb strings.Builder
+	var textStyles []*docs.Request
+	type paraRange struct {
+		start, end int64
+		para       mdParagraph
+	}
+	var paras []paraRange
+
+	pos := int64(1)
+	for _, p := range b.paragraphs {
+		start := pos
+		if b.bullets != "" {
+			// Nesting levels are set by leading tabs, which createParagraphBullets removes
+			tabs := strings.Repeat("\t", p.level)
+			sb.WriteString(tabs)
+			pos += utf16Len(tabs)
+		}
+		runs := mergeRuns(p.runs)
+		for _, r := range runs {
+			n := utf16Len(r.text)
+			sb.WriteString(r.text)
+			styleEnd := pos + n
+			if len(runs) == 1 && r.style.code {
+				// docs_get_content only reads a paragraph as a code block when its
+				// newline is monospace too
+				styleEnd++
+			}
+			if req := textStyleRequest(pos, styleEnd, r.style); req != nil {
+				textStyles = append(textStyles, req)
+			}
+			pos += n
+		}
+		sb.WriteString("\n")
+		pos++
+		paras = append(paras, paraRange{start: start, end: pos, para: p})
+	}
+
+	text := sb.String()
+	if fillEmpty {
+		text = strings.TrimSuffix(text, "\n")
+	}
+	if text == "" {
+		return nil
+	}
+	end := 1 + utf16Len(text)
+
+	requests := []*docs.Request{
+		{InsertText: &docs.InsertTextRequest{Location: &docs.Location{Index: 1}, Text: text}},
+		{UpdateTextStyle: &docs.UpdateTextStyleRequest{
+			Range:     &docs.Range{StartIndex: 1, EndIndex: end},
+			TextStyle: &docs.TextStyle{},
+			Fields:    "*",
+		}},
+	}
+	for _, pr := range paras {
+		style := &docs.ParagraphStyle{NamedStyleType: "NORMAL_TEXT"}
+		if pr.para.heading > 0 {
+			style.NamedStyleType = "HEADING_" + strconv.Itoa(pr.para.heading)
+		}
+		if pr.para.quote > 0 {
+			indent := &docs.Dimension{Magnitude: float64(pr.para.quote * quoteIndentPoints), Unit: "PT"}
+			style.IndentStart = indent
+			style.IndentFirstLine = indent
+		}
+		requests = append(requests, &docs.Request{UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+			Range:          &docs.Range{StartIndex: pr.start, EndIndex: max(min(pr.end, end), pr.start+1)},
+			ParagraphStyle: style,
+			Fields:         "namedStyleType,indentStart,indentFirstLine",
+		}})
+	}
+	if b.bullets == "" {
+		requests = append(requests, &docs.Request{DeleteParagraphBullets: &docs.DeleteParagraphBulletsRequest{
+			Range: &docs.Range{StartIndex: 1, EndIndex: end},
+		}})
+	}
+	requests = append(requests, textStyles...)
+	if b.bullets != "" {
+		// Last, since removing the leading tabs shifts the text after them
+		requests = append(requests, &docs.Request{CreateParagraphBullets: &docs.CreateParagraphBulletsRequest{
+			Range:        &docs.Range{StartIndex: 1, EndIndex: end},
+			BulletPreset: b.bullets,
+		}})
+	}
+	return requests
+}
+
+// mergeRuns joins adjacent runs with the same style.
+func mergeRuns(runs []mdRun) []mdRun {
+	var merged []mdRun
+	for _, r := range runs {
+		if r.text == "" {
+			continue
+		}
+		if n := len(merged); n > 0 && merged[n-1].style == r.style {
+			merged[n-1].text += r.text
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// textStyleRequest returns a request applying style to [start, end), or nil for
+// the default style.
+func textStyleRequest(start, end int64, style mdStyle) *docs.Request {
+	ts := &docs.TextStyle{}
+	var fields []string
+	if style.bold {
+		ts.Bold = true
+		fields = append(fields, "bold")
+	}
+	if style.italic {
+		ts.Italic = true
+		fields = append(fields, "italic")
+	}
+	if style.strike {
+		ts.Strikethrough = true
+		fields = append(fields, "strikethrough")
+	}
+	if style.code {
+		ts.WeightedFontFamily = &docs.WeightedFontFamily{FontFamily: codeFontFamily}
+		fields = append(fields, "weightedFontFamily")
+	}
+	if style.link != "" {
+		ts.Link = &docs.Link{Url: style.link}
+		fields = append(fields, "link")
+	}
+	if len(fields) == 0 || end <= start {
+		return nil
+	}
+	return &docs.Request{UpdateTextStyle: &docs.UpdateTextStyleRequest{
+		Range:     &docs.Range{StartIndex: start, EndIndex: end},
+		TextStyle: ts,
+		Fields:    strings.Join(fields, ","),
+	}}
+}
+
+// tableRequests inserts a table at index 1 and fills its cells. Docs inserts a
+// newline before the table, so the table starts at index 2; each row adds one index
+// and each cell two (its start and its empty paragraph), so cell (r, c) of an empty
+// table has its paragraph at 5 + r*(2*columns+1) + 2*c. Cells are filled last to
+// first so earlier cells keep those indexes.
+func tableRequests(b mdBlock) []*docs.Request {
+	rows := len(b.table)
+	columns := 0
+	for _, row := range b.table {
+		columns = max(columns, len(row))
+	}
+	if columns == 0 {
+		return nil
+	}
+
+	requests := []*docs.Request{{InsertTable: &docs.InsertTableRequest{
+		Location: &docs.Location{Index: 1},
+		Rows:     int64(rows),
+		Columns:  int64(columns),
+	}}}
+	if b.header {
+		requests = append(requests, &docs.Request{PinTableHeaderRows: &docs.PinTableHeaderRowsRequest{
+			TableStartLocation:    &docs.Location{Index: 2},
+			PinnedHeaderRowsCount: 1,
+		}})
+	}
+	for r := rows - 1; r >= 0; r-- {
+		for col := len(b.table[r]) - 1; col >= 0; col-- {
+			runs := mergeRuns(b.table[r][col])
+			if len(runs) == 0 {
+				continue
+			}
+			index := int64(5 + r*(2*columns+1) + 2*col)
+			requests = append(requests, &docs.Request{InsertText: &docs.InsertTextRequest{
+				Location: &docs.Location{Index: index},
+				Text:     runsText(runs),
+			}})
+			pos := index
+			for _, run := range runs {
+				n := utf16Len(run.text)
+				if req := textStyleRequest(pos, pos+n, run.style); req != nil {
+					requests = append(requests, req)
+				}
+				pos += n
+			}
+		}
+	}
+	return requests
+}
@@ -0,0 +1,620 @@
+package tools
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"google.golang.org/api/docs/v1"
+)
+
+// The tests below apply the requests from markdownToDocsRequests to simDoc, an
+// in-memory model of the parts of a Docs body the requests touch, and convert the
+// result back with convertDocumentTab. Indexes are UTF-16 code units, as in Docs.
+
+// simChar is one UTF-16 code unit of paragraph text and its style.
+type simChar struct {
+	unit  uint16
+	style docs.TextStyle
+}
+
+// simParagraph is a paragraph, including its trailing newline.
+type simParagraph struct {
+	chars  []simChar
+	style  docs.ParagraphStyle
+	bullet *docs.Bullet
+}
+
+// simElement is a structural element: a paragraph, a table, or a section break.
+type simElement struct {
+	para         *simParagraph
+	table        *simTable
+	sectionBreak bool
+}
+
+// simTable is a table whose cells hold paragraphs.
+type simTable struct {
+	rows   [][][]*simElement
+	header int // Pinned header rows
+}
+
+// simDoc is a document body and its lists.
+type simDoc struct {
+	body  []*simElement
+	lists map[string]docs.List
+}
+
+// paraRef locates a paragraph within its container.
+type paraRef struct {
+	container *[]*simElement
+	pos       int
+	start     int
+}
+
+// newSimDoc returns the body of a new document: a section break and an empty paragraph.
+func newSimDoc() *simDoc {
+	return &simDoc{
+		body: []*simElement{
+			{sectionBreak: true},
+			{para: &simParagraph{chars: []simChar{{unit: '\n'}}, style: docs.ParagraphStyle{NamedStyleType: "NORMAL_TEXT"}}},
+		},
+		lists: map[string]docs.List{},
+	}
+}
+
+// layout returns every paragraph in document order with its start index, and the
+// tables by start index.
+func (d *simDoc) layout() ([]paraRef, map[int]*simTable) {
+	var refs []paraRef
+	tables := map[int]*simTable{}
+	var walk func(container *[]*simElement, index int) int
+	walk = func(container *[]*simElement, index int) int {
+		for pos, elem := range *container {
+			switch {
+			case elem.para != nil:
+				refs = append(refs, paraRef{container: container, pos: pos, start: index})
+				index += len(elem.para.chars)
+			case elem.table != nil:
+				tables[index] = elem.table
+				index++ // Table start
+				for _, row := range elem.table.rows {
+					index++ // Row start
+					for c := range row {
+						index++ // Cell start
+						index = walk(&row[c], index)
+					}
+				}
+				index++ // Table end
+			case elem.sectionBreak:
+				index++
+			}
+		}
+		return index
+	}
+	walk(&d.body, 0)
+	return refs, tables
+}
+
+// paragraphAt returns the paragraph containing index.
+func (d *simDoc) paragraphAt(index int) (paraRef, *simParagraph, error) {
+	refs, _ := d.layout()
+	for _, ref := range refs {
+		p := (*ref.container)[ref.pos].para
+		if index >= ref.start && index < ref.start+len(p.chars) {
+			return ref, p, nil
+		}
+	}
+	return paraRef{}, nil, fmt.Errorf("index %d is not in a paragraph", index)
+}
+
+// overlapping returns the paragraphs that overlap [start, end).
+func (d *simDoc) overlapping(r *docs.Range) ([]*simParagraph, []int, error) {
+	if r.EndIndex <= r.StartIndex {
+		return nil, nil, fmt.Errorf("empty range [%d, %d)", r.StartIndex, r.EndIndex)
+	}
+	refs, _ := d.layout()
+	var paras []*simParagraph
+	var starts []int
+	for _, ref := range refs {
+		p := (*ref.container)[ref.pos].para
+		if ref.start < int(r.EndIndex) && ref.start+len(p.chars) > int(r.StartIndex) {
+			paras = append(paras, p)
+			starts = append(starts, ref.start)
+		}
+	}
+	if len(paras) == 0 {
+		return nil, nil, fmt.Errorf("range [%d, %d) is outside the document", r.StartIndex, r.EndIndex)
+	}
+	return paras, starts, nil
+}
+
+// replace replaces the element at ref with elems.
+func replace(ref paraRef, elems ...*simElement) {
+	c := *ref.container
+	*ref.container = append(c[:ref.pos:ref.pos], append(elems, c[ref.pos+1:]...)...)
+}
+
+// insertText inserts text, splitting paragraphs at newlines. New paragraphs keep
+// the style and bullet of the paragraph they were split from, and inserted text
+// takes the style of the text before it.
+func (d *simDoc) insertText(index int, text string) error {
+	ref, p, err := d.paragraphAt(index)
+	if err != nil {
+		return err
+	}
+	offset := index - ref.start
+	style := p.chars[max(offset-1, 0)].style
+	var inserted []simChar
+	for _, u := range utf16.Encode([]rune(text)) {
+		inserted = append(inserted, simChar{unit: u, style: style})
+	}
+	chars := append(append(append([]simChar(nil), p.chars[:offset]...), inserted...), p.chars[offset:]...)
+
+	var elems []*simElement
+	for len(chars) > 0 {
+		end := 0
+		for chars[end].unit != '\n' {
+			end++
+		}
+		split := &simParagraph{chars: chars[:end+1], style: p.style}
+		if p.bullet != nil {
+			bullet := *p.bullet
+			split.bullet = &bullet
+		}
+		elems = append(elems, &simElement{para: split})
+		chars = chars[end+1:]
+	}
+	replace(ref, elems...)
+	return nil
+}
+
+// insertBefore splits the paragraph at index with a newline and inserts elem
+// between the two parts, as Docs does for tables and section breaks.
+func (d *simDoc) insertBefore(index int, elem *simElement) error {
+	ref, p, err := d.paragraphAt(index)
+	if err != nil {
+		return err
+	}
+	offset := index - ref.start
+	before := &simParagraph{
+		chars: append(append([]simChar(nil), p.chars[:offset]...), simChar{unit: '\n'}),
+		style: p.style,
+	}
+	after := &simParagraph{chars: p.chars[offset:], style: p.style, bullet: p.bullet}
+	replace(ref, &simElement{para: before}, elem, &simElement{para: after})
+	return nil
+}
+
+// updateTextStyle applies the given fields of style to the text in r.
+func (d *simDoc) updateTextStyle(r *docs.Range, style *docs.TextStyle, fields string) error {
+	paras, starts, err := d.overlapping(r)
+	if err != nil {
+		return err
+	}
+	for i, p := range paras {
+		for j := range p.chars {
+			if index := starts[i] + j; index < int(r.StartIndex) || index >= int(r.EndIndex) {
+				continue
+			}
+			cs := &p.chars[j].style
+			for _, field := range strings.Split(fields, ",") {
+				switch field {
+				case "*":
+					*cs = *style
+				case "bold":
+					cs.Bold = style.Bold
+				case "italic":
+					cs.Italic = style.Italic
+				case "strikethrough":
+					cs.Strikethrough = style.Strikethrough
+				case "link":
+					cs.Link = style.Link
+				case "weightedFontFamily":
+					cs.WeightedFontFamily = style.WeightedFontFamily
+				default:
+					return fmt.Errorf("unsupported text style field %q", field)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// updateParagraphStyle applies the given fields of style to the paragraphs in r.
+func (d *simDoc) updateParagraphStyle(r *docs.Range, style *docs.ParagraphStyle, fields string) error {
+	paras, _, err := d.overlapping(r)
+	if err != nil {
+		return err
+	}
+	for _, p := range paras {
+		for _, field := range strings.Split(fields, ",") {
+			switch field {
+			case "namedStyleType":
+				p.style.NamedStyleType = style.NamedStyleType
+			case "indentStart":
+				p.style.IndentStart = style.IndentStart
+			case "indentFirstLine":
+				p.style.IndentFirstLine = style.IndentFirstLine
+			default:
+				return fmt.Errorf("unsupported paragraph style field %q", field)
+			}
+		}
+	}
+	return nil
+}
+
+// simPresetLevels are the nesting levels of the bullet presets markdownToDocsRequests uses.
+var simPresetLevels = map[string]func(level int) *docs.NestingLevel{
+	bulletPresetUnordered: func(level int) *docs.NestingLevel {
+		return &docs.NestingLevel{GlyphSymbol: []string{"●", "○", "■"}[level%3]}
+	},
+	bulletPresetOrdered: func(level int) *docs.NestingLevel {
+		return &docs.NestingLevel{
+			GlyphType:   []string{"DECIMAL", "ALPHA", "ROMAN"}[level%3],
+			GlyphFormat: fmt.Sprintf("%%%d.", level),
+		}
+	},
+	bulletPresetChecklist: func(level int) *docs.NestingLevel {
+		return &docs.NestingLevel{GlyphSymbol: "☐"}
+	},
+}
+
+// createBullets adds the paragraphs in r to a new list, setting each paragraph's
+// nesting level from its leading tabs and removing them.
+func (d *simDoc) createBullets(r *docs.Range, preset string) error {
+	levelFunc, ok := simPresetLevels[preset]
+	if !ok {
+		return fmt.Errorf("unsupported bullet preset %q", preset)
+	}
+	paras, _, err := d.overlapping(r)
+	if err != nil {
+		return err
+	}
+	listID := fmt.Sprintf("list.%d", len(d.lists))
+	var levels []*docs.NestingLevel
+	for level := range 9 {
+		levels = append(levels, levelFunc(level))
+	}
+	d.lists[listID] = docs.List{ListProperties: &docs.ListProperties{NestingLevels: levels}}
+
+	for _, p := range paras {
+		tabs := 0
+		for p.chars[tabs].unit == '\t' {
+			tabs++
+		}
+		p.chars = p.chars[tabs:]
+		p.bullet = &docs.Bullet{ListId: listID, NestingLevel: int64(tabs)}
+	}
+	return nil
+}
+
+// apply applies batchUpdate requests in order.
+func (d *simDoc) apply(requests []*docs.Request) error {
+	for i, req := range requests {
+		var err error
+		switch {
+		case req.InsertText != nil:
+			err = d.insertText(int(req.InsertText.Location.Index), req.InsertText.Text)
+		case req.UpdateTextStyle != nil:
+			r := req.UpdateTextStyle
+			err = d.updateTextStyle(r.Range, r.TextStyle, r.Fields)
+		case req.UpdateParagraphStyle != nil:
+			r := req.UpdateParagraphStyle
+			err = d.updateParagraphStyle(r.Range, r.ParagraphStyle, r.Fields)
+		case req.DeleteParagraphBullets != nil:
+			var paras []*simParagraph
+			paras, _, err = d.overlapping(req.DeleteParagraphBullets.Range)
+			for _, p := range paras {
+				p.bullet = nil
+			}
+		case req.CreateParagraphBullets != nil:
+			r := req.CreateParagraphBullets
+			err = d.createBullets(r.Range, r.BulletPreset)
+		case req.InsertTable != nil:
+			r := req.InsertTable
+			table := &simTable{}
+			for range r.Rows {
+				var row [][]*simElement
+				for range r.Columns {
+					row = append(row, []*simElement{{para: &simParagraph{chars: []simChar{{unit: '\n'}}}}})
+				}
+				table.rows = append(table.rows, row)
+			}
+			err = d.insertBefore(int(r.Location.Index), &simElement{table: table})
+		case req.PinTableHeaderRows != nil:
+			r := req.PinTableHeaderRows
+			_, tables := d.layout()
+			table, ok := tables[int(r.TableStartLocation.Index)]
+			if !ok {
+				err = fmt.Errorf("no table starts at index %d", r.TableStartLocation.Index)
+				break
+			}
+			table.header = int(r.PinnedHeaderRowsCount)
+		case req.InsertSectionBreak != nil:
+			err = d.insertBefore(int(req.InsertSectionBreak.Location.Index), &simElement{sectionBreak: true})
+		default:
+			err = fmt.Errorf("unsupported request")
+		}
+		if err != nil {
+			return fmt.Errorf("request %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// documentTab converts the model to the structure returned by the Docs API.
+func (d *simDoc) documentTab() *docs.DocumentTab {
+	var convert func(elems []*simElement, index int) ([]*docs.StructuralElement, int)
+	convert = func(elems []*simElement, index int) ([]*docs.StructuralElement, int) {
+		var content []*docs.StructuralElement
+		for _, elem := range elems {
+			se := &docs.StructuralElement{StartIndex: int64(index)}
+			switch {
+			case elem.para != nil:
+				se.Paragraph = elem.para.paragraph(index)
+				index += len(elem.para.chars)
+			case elem.table != nil:
+				table := &docs.Table{Rows: int64(len(elem.table.rows)), Columns: int64(len(elem.table.rows[0]))}
+				index++
+				for r, row := range elem.table.rows {
+					tr := &docs.TableRow{TableRowStyle: &docs.TableRowStyle{TableHeader: r < elem.table.header}}
+					index++
+					for _, cell := range row {
+						index++
+						var cellContent []*docs.StructuralElement
+						cellContent, index = convert(cell, index)
+						tr.TableCells = append(tr.TableCells, &docs.TableCell{Content: cellContent})
+					}
+					table.TableRows = append(table.TableRows, tr)
+				}
+				index++
+				se.Table = table
+			case elem.sectionBreak:
+				se.SectionBreak = &docs.SectionBreak{}
+				index++
+			}
+			se.EndIndex = int64(index)
+			content = append(content, se)
+		}
+		return content, index
+	}
+	content, _ := convert(d.body, 0)
+	lists := map[string]docs.List{}
+	for id, list := range d.lists {
+		lists[id] = list
+	}
+	return &docs.DocumentTab{Body: &docs.Body{Content: content}, Lists: lists}
+}
+
+// paragraph converts a paragraph starting at index, grouping text into runs by style.
+func (p *simParagraph) paragraph(index int) *docs.Paragraph {
+	style := p.style
+	para := &docs.Paragraph{ParagraphStyle: &style, Bullet: p.bullet}
+	for start := 0; start < len(p.chars); {
+		end := start + 1
+		for end < len(p.chars) && reflect.DeepEqual(p.chars[end].style, p.chars[start].style) {
+			end++
+		}
+		var units []uint16
+		for _, c := range p.chars[start:end] {
+			units = append(units, c.unit)
+		}
+		runStyle := p.chars[start].style
+		para.Elements = append(para.Elements, &docs.ParagraphElement{
+			StartIndex: int64(index + start),
+			EndIndex:   int64(index + end),
+			TextRun:    &docs.TextRun{Content: string(utf16.Decode(units)), TextStyle: &runStyle},
+		})
+		start = end
+	}
+	return para
+}
+
+// roundTrip creates a document from markdown in a simDoc and converts it back.
+func roundTrip(t *testing.T, markdown string) (string, *simDoc) {
+	t.Helper()
+	requests, _, _ := markdownToDocsRequests(markdown)
+	doc := newSimDoc()
+	if err := doc.apply(requests); err != nil {
+		t.Fatalf("applying requests: %v", err)
+	}
+	converted, _ := convertDocumentTab(doc.documentTab(), markdownOptions{})
+	return converted, doc
+}
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string // Expected docs_get_content Markdown, if it differs from markdown
+	}{
+		{
+			name:     "headings",
+			markdown: "# Title\n\nIntro text.\n\n## Section\n\nBody.\n\n### Subsection\n\n###### Deepest",
+		},
+		{
+			name:     "emphasis",
+			markdown: "Some **bold**, *italic*, ***both***, and ~~struck~~ text.",
+		},
+		{
+			name:     "links",
+			markdown: "See [the docs](https://example.com/docs) and [**bold link**](https://example.com).",
+		},
+		{
+			name:     "autolink",
+			markdown: "Visit <https://example.com> today.",
+			want:     "Visit [https://example.com](https://example.com) today.",
+		},
+		{
+			name:     "relative link",
+			markdown: "See [the guide](guide.md).",
+			want:     "See the guide.",
+		},
+		{
+			name:     "inline code",
+			markdown: "Run `go test ./...` before `git push`.",
+		},
+		{
+			name:     "escaped characters",
+			markdown: "Use snake\\_case and \\*stars\\* literally.",
+		},
+		{
+			name:     "non-BMP characters",
+			markdown: "Party 🎉 then **bold 🚀 text** and *more*.",
+		},
+		{
+			name:     "code block",
+			markdown: "Before:\n\n```\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n```\n\nAfter.",
+		},
+		{
+			name:     "code block language",
+			markdown: "```go\nx := 1\n```",
+			want:     "```\nx := 1\n```",
+		},
+		{
+			name:     "unordered nested list",
+			markdown: "- one\n- two\n  - nested\n    - deeper\n- three",
+		},
+		{
+			name:     "ordered list",
+			markdown: "1. first\n2. second with **bold**\n3. third",
+		},
+		{
+			name:     "ordered nested list",
+			markdown: "1. first\n   1. sub\n   2. sub two\n2. second",
+		},
+		{
+			name:     "ordered list with two-digit numbers",
+			markdown: "1. a\n2. b\n3. c\n4. d\n5. e\n6. f\n7. g\n8. h\n9. i\n10. j\n    - nested\n11. k",
+			want:     "1. a\n2. b\n3. c\n4. d\n5. e\n6. f\n7. g\n8. h\n9. i\n10. j\n    1. nested\n11. k",
+		},
+		{
+			name:     "mixed nested lists",
+			markdown: "- item\n  1. first\n  2. second\n     - deep\n- next",
+			want:     "- item\n  - first\n  - second\n    - deep\n- next",
+		},
+		{
+			name:     "nested task list",
+			markdown: "- [ ] parent\n  - [x] child\n- [ ] sibling",
+		},
+		{
+			name:     "task list",
+			markdown: "- [ ] todo\n- [x] done\n- [ ] later",
+		},
+		{
+			name:     "table with header",
+			markdown: "| Name | Value |\n| --- | --- |\n| a | **b** |\n| `c` | [d](https://example.com) |",
+		},
+		{
+			name:     "table without header",
+			markdown: "|  |  |\n| --- | --- |\n| a | b |\n| c | d |",
+		},
+		{
+			name:     "horizontal rule",
+			markdown: "Before\n\n---\n\nAfter",
+		},
+		{
+			name:     "block quote",
+			markdown: "> Quoted text",
+			want:     "Quoted text",
+		},
+		{
+			name:     "inline code paragraph",
+			markdown: "`code only`",
+			want:     "```\ncode only\n```",
+		},
+		{
+			name: "mixed document",
+			markdown: "# Plan\n\nGoals for the **quarter**.\n\n- ship it\n- [docs](https://example.com)\n\n" +
+				"| Owner | Task |\n| --- | --- |\n| me | write |\n\n---\n\n## Notes\n\n```\nmake build\n```\n\nDone.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := roundTrip(t, tt.markdown)
+			want := tt.want
+			if want == "" {
+				want = tt.markdown
+			}
+			if strings.TrimSpace(got) != strings.TrimSpace(want) {
+				t.Errorf("round trip of\n%s\ngot\n%s\nwant\n%s", tt.markdown, got, want)
+			}
+			// The Markdown read back must parse to the same document
+			if again, _ := roundTrip(t, got); strings.TrimSpace(again) != strings.TrimSpace(got) {
+				t.Errorf("second round trip of\n%s\ngot\n%s", got, again)
+			}
+		})
+	}
+}
+
+func TestMarkdownToDocsRequestsStyles(t *testing.T) {
+	_, doc := roundTrip(t, "# Heading\n\n> quoted\n\n```\ncode\n```")
+	refs, _ := doc.layout()
+	var paras []*simParagraph
+	for _, ref := range refs {
+		if p := (*ref.container)[ref.pos].para; len(p.chars) > 1 {
+			paras = append(paras, p)
+		}
+	}
+	if len(paras) != 3 {
+		t.Fatalf("got %d non-empty paragraphs, want 3", len(paras))
+	}
+	if got := paras[0].style.NamedStyleType; got != "HEADING_1" {
+		t.Errorf("heading style = %q, want HEADING_1", got)
+	}
+	if indent := paras[1].style.IndentStart; indent == nil || indent.Magnitude != quoteIndentPoints {
+		t.Errorf("quote indent = %v, want %d", indent, quoteIndentPoints)
+	}
+	if font := paras[2].chars[0].style.WeightedFontFamily; font == nil || font.FontFamily != codeFontFamily {
+		t.Errorf("code font = %v, want %s", font, codeFontFamily)
+	}
+}
+
+func TestMarkdownToDocsRequestsTitleAndNotes(t *testing.T) {
+	tests := []struct {
+		name      string
+		markdown  string
+		wantTitle string
+		wantNotes []string
+	}{
+		{
+			name:      "first heading",
+			markdown:  "Intro\n\n## First *heading*\n\n# Second",
+			wantTitle: "First heading",
+		},
+		{
+			name:     "no heading",
+			markdown: "Just text.",
+		},
+		{
+			name:     "lossy conversions",
+			markdown: "> quote\n\n![alt](https://example.com/a.png) [rel](a.md) <b>x</b>\n\n<div>block</div>",
+			wantNotes: []string{
+				"block quotes were inserted as indented paragraphs",
+				"images were inserted as links",
+				"relative links were inserted as plain text",
+				"inline HTML was inserted as plain text",
+				"HTML blocks were inserted as plain text",
+			},
+		},
+		{
+			name:      "mixed nested lists",
+			markdown:  "1. one\n   - bullet",
+			wantNotes: []string{"nested lists were given the style of their outermost list"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, title, notes := markdownToDocsRequests(tt.markdown)
+			if title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", title, tt.wantTitle)
+			}
+			if !reflect.DeepEqual(notes, tt.wantNotes) {
+				t.Errorf("notes = %q, want %q", notes, tt.wantNotes)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/joelanford/mcp/google-workspace-mcp/types"
+)
+
+// defaultMaxItems caps the number of items collected when fetch_all is set without max_items.
+const defaultMaxItems = 1000
+
+// maxFetchAllItems is the hard upper bound for max_items.
+const maxFetchAllItems = 10000
+
+// fetchPageFunc fetches a single page of results starting at pageToken, requesting at most
+// pageSize items. It returns the page's items and the token for the next page.
+type fetchPageFunc[T any] func(ctx context.Context, pageToken string, pageSize int) ([]T, string, error)
+
+// pagedResult holds the merged results of following page tokens.
+type pagedResult[T any] struct {
+	Items         []T
+	NextPageToken string // Token to resume from if results were truncated
+	Truncated     bool   // True if max_items was reached before the last page
+}
+
+// fetchAllPages follows page tokens starting at pageToken until there are no more pages or
+// maxItems items have been collected. Each page request waits on the global rate limiter,
+// and progress is reported after each page.
+func fetchAllPages[T any](ctx context.Context, pageToken string, pageSize, maxItems int, progress *types.Progress, fetch fetchPageFunc[T]) (pagedResult[T], error) {
+	if maxItems <= 0 {
+		maxItems = defaultMaxItems
+	}
+	maxItems = min(maxItems, maxFetchAllItems)
+
+	var result pagedResult[T]
+	for {
+		if err := types.GlobalRateLimiter.Wait(ctx); err != nil {
+			return result, err
+		}
+
+		// Only request what's left so the next page token never skips items
+		items, next, err := fetch(ctx, pageToken, min(pageSize, maxItems-len(result.Items)))
+		if err != nil {
+			return result, err
+		}
+		result.Items = append(result.Items, items...)
+		progress.Report(float64(len(result.Items)), float64(maxItems), fmt.Sprintf("fetched %d items", len(result.Items)))
+
+		if next == "" {
+			return result, nil
+		}
+		if len(result.Items) >= maxItems {
+			result.NextPageToken = next
+			result.Truncated = true
+			return result, nil
+		}
+		pageToken = next
+	}
+}
+
+// withFetchAll adds the fetch_all and max_items arguments to a list tool.
+func withFetchAll() mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		mcp.WithBoolean("fetch_all",
+			mcp.Description("Follow page tokens and merge all pages into one response (up to max_items)"),
+		)(t)
+		mcp.WithNumber("max_items",
+			mcp.Description(fmt.Sprintf("Maximum total items to return when fetch_all is set (default %d, max %d)", defaultMaxItems, maxFetchAllItems)),
+			mcp.Min(1),
+			mcp.Max(maxFetchAllItems),
+		)(t)
+	}
+}
@@ -0,0 +1,257 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"sync"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
+
+	"github.com/joelanford/mcp/google-workspace-mcp/types"
+)
+
+// maxFolderDepth bounds the ancestor walk when checking folder policies.
+const maxFolderDepth = 32
+
+// gmailLabelNames returns a map of label ID to label name, or nil if no label
+// rules are configured and names aren't needed.
+func (g *GmailTools) gmailLabelNames(ctx context.Context) (map[string]string, error) {
+	if !types.GlobalPolicy.Gmail.HasLabelRules() {
+		return nil, nil
+	}
+	labelList, err := g.gmailService.Users.Labels.List("me").Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list labels for policy check: %w", err)
+	}
+	names := make(map[string]string, len(labelList.Labels))
+	for _, l := range labelList.Labels {
+		names[l.Id] = l.Name
+	}
+	return names, nil
+}
+
+// checkMessagePolicy checks a message (fetched with at least metadata format)
+// against the Gmail access policy.
+func checkMessagePolicy(msg *gmail.Message, labelNames map[string]string) error {
+	policy := types.GlobalPolicy.Gmail
+	if !policy.Enabled() {
+		return nil
+	}
+
+	// Match against both label IDs and names
+	labels := make([]string, 0, 2*len(msg.LabelIds))
+	for _, id := range msg.LabelIds {
+		labels = append(labels, id)
+		if name, ok := labelNames[id]; ok {
+			labels = append(labels, name)
+		}
+	}
+
+	var sender string
+	if msg.Payload != nil {
+		for _, header := range msg.Payload.Headers {
+			if strings.EqualFold(header.Name, "from") {
+				sender = header.Value
+				if addr, err := mail.ParseAddress(header.Value); err == nil {
+					sender = addr.Address
+				}
+				break
+			}
+		}
+	}
+
+	return policy.Check(labels, sender)
+}
+
+// checkPolicy checks a single message against the Gmail access policy.
+func (g *GmailTools) checkPolicy(ctx context.Context, msg *gmail.Message) error {
+	if !types.GlobalPolicy.Gmail.Enabled() {
+		return nil
+	}
+	labelNames, err := g.gmailLabelNames(ctx)
+	if err != nil {
+		return err
+	}
+	return checkMessagePolicy(msg, labelNames)
+}
+
+// denyLabelQuery returns Gmail search terms that exclude messages with denied labels,
+// so fewer results are dropped after listing. Only literal label names can be
+// searched for; filterSearchResults still enforces every rule.
+func denyLabelQuery() string {
+	var terms []string
+	for _, pattern := range types.GlobalPolicy.Gmail.DenyLabels {
+		if strings.ContainsAny(pattern, `*?[\"`) {
+			continue
+		}
+		terms = append(terms, `-label:"`+pattern+`"`)
+	}
+	return strings.Join(terms, " ")
+}
+
+// filterSearchResults drops search results for messages denied by the Gmail policy.
+// Each result requires a metadata fetch, so this is only done when rules are configured.
+func (g *GmailTools) filterSearchResults(ctx context.Context, results []GmailSearchResult) ([]GmailSearchResult, error) {
+	if !types.GlobalPolicy.Gmail.Enabled() {
+		return results, nil
+	}
+	labelNames, err := g.gmailLabelNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := results[:0]
+	for _, r := range results {
+		if err := types.GlobalRateLimiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		msg, err := g.gmailService.Users.Messages.Get("me", r.MessageID).
+			Context(ctx).
+			Format("metadata").
+			MetadataHeaders("From").
+			Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get message for policy check: %w", err)
+		}
+		if checkMessagePolicy(msg, labelNames) == nil {
+			allowed = append(allowed, r)
+		}
+	}
+	return allowed, nil
+}
+
+// docsPolicyFields are the Drive file fields needed to evaluate the Docs policy.
+const docsPolicyFields = "parents, owners(emailAddress)"
+
+// filePolicy evaluates the Docs access policy for Drive files. It is shared by the
+// Docs and Drive tools, so the Docs policy also covers every other Drive file type.
+type filePolicy struct {
+	driveService *drive.Service
+
+	// folderCache maps folder IDs to their parents for access policy checks.
+	folderMu    sync.Mutex
+	folderCache map[string][]string
+}
+
+// newFilePolicy creates a filePolicy that looks up folders with the given Drive service.
+func newFilePolicy(driveService *drive.Service) *filePolicy {
+	return &filePolicy{
+		driveService: driveService,
+		folderCache:  map[string][]string{},
+	}
+}
+
+// checkFilePolicy checks a Drive file (fetched with docsPolicyFields) against the Docs access policy.
+func (fp *filePolicy) checkFilePolicy(ctx context.Context, f *drive.File) error {
+	policy := types.GlobalPolicy.Docs
+	if !policy.Enabled() {
+		return nil
+	}
+
+	var folders []string
+	if policy.HasFolderRules() {
+		var err error
+		folders, err = fp.folderAncestors(ctx, f.Parents)
+		if err != nil {
+			return err
+		}
+	}
+
+	owners := make([]string, 0, len(f.Owners))
+	for _, o := range f.Owners {
+		owners = append(owners, o.EmailAddress)
+	}
+
+	return policy.Check(folders, owners)
+}
+
+// checkDocumentPolicy fetches a document's parents and owners and checks them against the Docs policy.
+func (fp *filePolicy) checkDocumentPolicy(ctx context.Context, documentID string) error {
+	if !types.GlobalPolicy.Docs.Enabled() {
+		return nil
+	}
+	f, err := fp.driveService.Files.Get(documentID).
+		Context(ctx).
+		Fields(docsPolicyFields).
+		SupportsAllDrives(true).
+		Do()
+	if err != nil {
+		return fmt.Errorf("failed to get document metadata for policy check: %w", err)
+	}
+	return fp.checkFilePolicy(ctx, f)
+}
+
+// folderAncestors returns the given parent folder IDs and all of their ancestors.
+// Folder parents are cached for the lifetime of the filePolicy.
+func (fp *filePolicy) folderAncestors(ctx context.Context, parents []string) ([]string, error) {
+	seen := map[string]bool{}
+	var result []string
+	queue := append([]string(nil), parents...)
+
+	for depth := 0; len(queue) > 0 && depth < maxFolderDepth; depth++ {
+		var next []string
+		for _, id := range queue {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			result = append(result, id)
+
+			folderParents, err := fp.folderParents(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, folderParents...)
+		}
+		queue = next
+	}
+	return result, nil
+}
+
+// folderParents returns the parent IDs of a folder, using the cache if possible.
+func (fp *filePolicy) folderParents(ctx context.Context, folderID string) ([]string, error) {
+	fp.folderMu.Lock()
+	parents, ok := fp.folderCache[folderID]
+	fp.folderMu.Unlock()
+	if ok {
+		return parents, nil
+	}
+
+	f, err := fp.driveService.Files.Get(folderID).
+		Context(ctx).
+		Fields("parents").
+		SupportsAllDrives(true).
+		Do()
+	var apiErr *googleapi.Error
+	switch {
+	case errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound:
+		// Folders the user can't see end the ancestor chain
+		f = &drive.File{}
+	case err != nil:
+		return nil, fmt.Errorf("failed to get folder %s for policy check: %w", folderID, err)
+	}
+
+	fp.folderMu.Lock()
+	fp.folderCache[folderID] = f.Parents
+	fp.folderMu.Unlock()
+	return f.Parents, nil
+}
+
+// resolveCalendarID resolves "primary" to the user's actual calendar ID so
+// policy patterns can match it. Other IDs are returned unchanged.
+func (c *CalendarTools) resolveCalendarID(ctx context.Context, calendarID string) (string, error) {
+	if calendarID != "primary" || !types.GlobalPolicy.Calendar.Enabled() {
+		return calendarID, nil
+	}
+	entry, err := c.calendarService.CalendarList.Get("primary").Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve primary calendar for policy check: %w", err)
+	}
+	return entry.Id, nil
+}
@@ -3,6 +3,7 @@ package types
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"golang.org/x/oauth2/google"
@@ -21,19 +22,32 @@ type Clients struct {
 	docs     *docs.Service
 	drive    *drive.Service
 	gmail    *gmail.Service
+
+	// docsWrite and driveWrite have write scopes. They are only created in write
+	// mode and only used by tools that modify data, so every read runs read-only.
+	docsWrite  *docs.Service
+	driveWrite *drive.Service
+
+	// docsHTTP is an authenticated HTTP client for fetching Docs content URIs (e.g. images).
+	docsHTTP *http.Client
 }
 
 // RequiredScopes returns all scopes needed by the clients.
 func RequiredScopes() []string {
-	return []string{
+	scopes := []string{
 		calendar.CalendarReadonlyScope,
 		docs.DocumentsReadonlyScope,
 		drive.DriveReadonlyScope,
 		gmail.GmailReadonlyScope,
 	}
+	if GlobalWriteMode {
+		scopes = append(scopes, docs.DocumentsScope, drive.DriveScope)
+	}
+	return scopes
 }
 
-// NewClients creates all Google API clients with read-only scopes.
+// NewClients creates all Google API clients with read-only scopes, plus write-scoped
+// Docs and Drive clients if write mode is enabled.
 // It validates that Application Default Credentials are available.
 func NewClients(ctx context.Context) (*Clients, error) {
 	scopes := RequiredScopes()
@@ -75,24 +89,66 @@ func NewClients(ctx context.Context) (*Clients, error) {
 		return nil, fmt.Errorf("failed to create gmail service: %w", err)
 	}
 
-	return &Clients{
+	docsHTTPClient, err := google.DefaultClient(ctx, docs.DocumentsReadonlyScope, drive.DriveReadonlyScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create docs HTTP client: %w", err)
+	}
+
+	clients := &Clients{
 		calendar: calendarService,
 		docs:     docsService,
 		drive:    driveService,
 		gmail:    gmailService,
-	}, nil
+		docsHTTP: docsHTTPClient,
+	}
+
+	if GlobalWriteMode {
+		clients.docsWrite, err = docs.NewService(ctx,
+			option.WithScopes(docs.DocumentsScope),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create docs write service: %w", err)
+		}
+
+		clients.driveWrite, err = drive.NewService(ctx,
+			option.WithScopes(drive.DriveScope),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create drive write service: %w", err)
+		}
+	}
+
+	return clients, nil
 }
 
 // DocsClients provides access to services needed by Docs tools.
 type DocsClients struct {
-	Docs  *docs.Service
-	Drive *drive.Service
+	Docs       *docs.Service
+	Drive      *drive.Service
+	HTTP       *http.Client   // Authenticated client for document content URIs
+	DocsWrite  *docs.Service  // Write-scoped; nil unless write mode is enabled
+	DriveWrite *drive.Service // Write-scoped; nil unless write mode is enabled
 }
 
 // ForDocs returns clients scoped for Docs tools.
 func (c *Clients) ForDocs() *DocsClients {
 	return &DocsClients{
-		Docs:  c.docs,
+		Docs:       c.docs,
+		Drive:      c.drive,
+		HTTP:       c.docsHTTP,
+		DocsWrite:  c.docsWrite,
+		DriveWrite: c.driveWrite,
+	}
+}
+
+// DriveClients provides access to services needed by Drive tools.
+type DriveClients struct {
+	Drive *drive.Service
+}
+
+// ForDrive returns clients scoped for Drive tools.
+func (c *Clients) ForDrive() *DriveClients {
+	return &DriveClients{
 		Drive: c.drive,
 	}
 }
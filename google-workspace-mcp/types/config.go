@@ -16,10 +16,30 @@ const (
 // GlobalOutputFormat controls the default output format for MCP responses.
 var GlobalOutputFormat = OutputFormatCompact
 
+// GlobalHardenUntrusted wraps attacker-controllable content (email bodies, document
+// text, event descriptions) in delimited blocks, strips invisible characters, and
+// reports suspicious instruction-like patterns.
+var GlobalHardenUntrusted = false
+
+// GlobalWriteMode enables tools that modify Google Workspace data (e.g. adding
+// comments) and requests the broader OAuth scopes they need.
+var GlobalWriteMode = false
+
+// GlobalExportDir is the local directory exported files are saved to when a tool is
+// asked to save instead of returning content inline. Saving is disabled when empty.
+var GlobalExportDir = ""
+
 func init() {
 	if os.Getenv("MCP_OUTPUT_FORMAT") == "json" {
 		GlobalOutputFormat = OutputFormatJSON
 	}
+	if os.Getenv("MCP_HARDEN_UNTRUSTED") == "true" {
+		GlobalHardenUntrusted = true
+	}
+	if os.Getenv("MCP_WRITE_MODE") == "true" {
+		GlobalWriteMode = true
+	}
+	GlobalExportDir = os.Getenv("MCP_EXPORT_DIR")
 }
 
 // CompactMarshaler is implemented by types that support compact text output.
@@ -28,6 +48,7 @@ type CompactMarshaler interface {
 }
 
 // MarshalResponse marshals a value to string, using compact format if available.
+// Output filters are applied to the whole tool result by FilterMiddleware.
 func MarshalResponse(v any) (string, error) {
 	if GlobalOutputFormat == OutputFormatCompact {
 		if cm, ok := v.(CompactMarshaler); ok {
@@ -35,5 +56,8 @@ func MarshalResponse(v any) (string, error) {
 		}
 	}
 	data, err := json.Marshal(v)
-	return string(data), err
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
 }
@@ -0,0 +1,125 @@
+package types
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// OutputFilter transforms text in tool output before it is returned to the client.
+type OutputFilter interface {
+	FilterText(s string) string
+}
+
+// GlobalOutputFilters are applied, in order, to every tool result by FilterMiddleware.
+var GlobalOutputFilters []OutputFilter
+
+// binaryWithheld replaces image and binary resource content while output filters are
+// configured, since filters can only inspect text.
+const binaryWithheld = "[binary content withheld: output filters can't inspect it]"
+
+// FilterMiddleware returns a tool middleware that applies GlobalOutputFilters to every
+// tool result, including errors. Text that is valid JSON is filtered string by string.
+// Images, audio, and binary resources are replaced with a notice.
+func FilterMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if len(GlobalOutputFilters) == 0 {
+				return result, err
+			}
+			if err != nil {
+				return result, errors.New(applyOutputFilters(err.Error()))
+			}
+			if result != nil {
+				result.Content = filterContent(result.Content)
+			}
+			return result, nil
+		}
+	}
+}
+
+// filterContent applies output filters to tool result content.
+func filterContent(content []mcp.Content) []mcp.Content {
+	filtered := make([]mcp.Content, 0, len(content))
+	for _, c := range content {
+		switch c := c.(type) {
+		case mcp.TextContent:
+			c.Text = filterText(c.Text)
+			filtered = append(filtered, c)
+		case mcp.EmbeddedResource:
+			switch r := c.Resource.(type) {
+			case mcp.TextResourceContents:
+				r.Text = filterText(r.Text)
+				c.Resource = r
+				filtered = append(filtered, c)
+			default:
+				filtered = append(filtered, mcp.NewTextContent(binaryWithheld))
+			}
+		case mcp.ImageContent, mcp.AudioContent:
+			filtered = append(filtered, mcp.NewTextContent(binaryWithheld))
+		default:
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+// filterText applies output filters to text, filtering JSON string by string.
+func filterText(s string) string {
+	if json.Valid([]byte(s)) {
+		if data, err := applyOutputFiltersJSON([]byte(s)); err == nil {
+			return string(data)
+		}
+	}
+	return applyOutputFilters(s)
+}
+
+// applyOutputFilters runs all configured filters over plain text.
+func applyOutputFilters(s string) string {
+	for _, f := range GlobalOutputFilters {
+		s = f.FilterText(s)
+	}
+	return s
+}
+
+// applyOutputFiltersJSON runs all configured filters over every string value in
+// JSON output. Filtering decoded values (rather than the encoded text) keeps escape
+// sequences such as "\n" from being mistaken for part of a match.
+func applyOutputFiltersJSON(data []byte) ([]byte, error) {
+	if len(GlobalOutputFilters) == 0 {
+		return data, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(filterValue(v))
+}
+
+// filterValue recursively applies output filters to string values.
+func filterValue(v any) any {
+	switch t := v.(type) {
+	case string:
+		return applyOutputFilters(t)
+	case []any:
+		for i := range t {
+			t[i] = filterValue(t[i])
+		}
+		return t
+	case map[string]any:
+		for k := range t {
+			t[k] = filterValue(t[k])
+		}
+		return t
+	default:
+		return v
+	}
+}
@@ -0,0 +1,87 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestFilterMiddleware(t *testing.T) {
+	r, err := NewRedactor(RedactConfig{Rules: []string{"email"}})
+	if err != nil {
+		t.Fatalf("NewRedactor: %v", err)
+	}
+	GlobalOutputFilters = []OutputFilter{r}
+	defer func() { GlobalOutputFilters = nil }()
+
+	tests := []struct {
+		name    string
+		result  *mcp.CallToolResult
+		err     error
+		want    []mcp.Content
+		wantErr string
+	}{
+		{
+			name:   "error result",
+			result: mcp.NewToolResultError("access denied: sender bob@corp.io"),
+			want:   []mcp.Content{mcp.NewTextContent("access denied: sender [EMAIL_1]")},
+		},
+		{
+			name:   "JSON text",
+			result: mcp.NewToolResultText(`{"from":"bob@corp.io","body":"line\nalice@corp.io"}`),
+			want:   []mcp.Content{mcp.NewTextContent(`{"body":"line\n[EMAIL_2]","from":"[EMAIL_1]"}`)},
+		},
+		{
+			name: "image",
+			result: &mcp.CallToolResult{Content: []mcp.Content{
+				mcp.NewTextContent("Image by bob@corp.io"),
+				mcp.NewImageContent("iVBORw0KGgo=", "image/png"),
+			}},
+			want: []mcp.Content{
+				mcp.NewTextContent("Image by [EMAIL_1]"),
+				mcp.NewTextContent(binaryWithheld),
+			},
+		},
+		{
+			name:   "text resource",
+			result: mcp.NewToolResultResource("export", mcp.TextResourceContents{URI: "u", Text: "to bob@corp.io"}),
+			want: []mcp.Content{
+				mcp.NewTextContent("export"),
+				mcp.EmbeddedResource{Type: mcp.ContentTypeResource, Resource: mcp.TextResourceContents{URI: "u", Text: "to [EMAIL_1]"}},
+			},
+		},
+		{
+			name:   "binary resource",
+			result: mcp.NewToolResultResource("export", mcp.BlobResourceContents{URI: "u", Blob: "UEsDBA=="}),
+			want:   []mcp.Content{mcp.NewTextContent("export"), mcp.NewTextContent(binaryWithheld)},
+		},
+		{
+			name:    "handler error",
+			err:     errors.New("bad argument bob@corp.io"),
+			wantErr: "bad argument [EMAIL_1]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := FilterMiddleware()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return tt.result, tt.err
+			})
+			result, err := handler(context.Background(), mcp.CallToolRequest{})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result.Content, tt.want) {
+				t.Errorf("content = %#v, want %#v", result.Content, tt.want)
+			}
+		})
+	}
+}
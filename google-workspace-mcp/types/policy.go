@@ -0,0 +1,172 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// ErrPolicyDenied is returned when an access policy blocks an item.
+var ErrPolicyDenied = errors.New("denied by access policy")
+
+// Policy restricts which Gmail messages, documents, and calendars tools may return.
+// It is loaded from the JSON file named by MCP_POLICY_CONFIG.
+//
+// Patterns are case-insensitive globs (see globMatch), e.g. "*@hr.example.com"
+// or "HR/*". Deny rules always win. If an allow list is set, an item must match
+// at least one of its patterns.
+type Policy struct {
+	Gmail    GmailPolicy    `json:"gmail"`
+	Docs     DocsPolicy     `json:"docs"`
+	Calendar CalendarPolicy `json:"calendar"`
+}
+
+// GmailPolicy restricts messages by label name (or ID) and sender address.
+type GmailPolicy struct {
+	AllowLabels  []string `json:"allow_labels,omitempty"`
+	DenyLabels   []string `json:"deny_labels,omitempty"`
+	AllowSenders []string `json:"allow_senders,omitempty"`
+	DenySenders  []string `json:"deny_senders,omitempty"`
+}
+
+// DocsPolicy restricts documents by ancestor folder ID and owner email.
+type DocsPolicy struct {
+	AllowFolders []string `json:"allow_folders,omitempty"`
+	DenyFolders  []string `json:"deny_folders,omitempty"`
+	AllowOwners  []string `json:"allow_owners,omitempty"`
+	DenyOwners   []string `json:"deny_owners,omitempty"`
+}
+
+// CalendarPolicy restricts calendars by ID.
+type CalendarPolicy struct {
+	AllowCalendars []string `json:"allow_calendars,omitempty"`
+	DenyCalendars  []string `json:"deny_calendars,omitempty"`
+}
+
+// GlobalPolicy is the access policy enforced by all tools. The zero value allows everything.
+var GlobalPolicy = &Policy{}
+
+// LoadPolicy loads the access policy named by MCP_POLICY_CONFIG.
+// It returns an empty (allow-all) policy if none is configured.
+func LoadPolicy() (*Policy, error) {
+	p := &Policy{}
+	file := os.Getenv("MCP_POLICY_CONFIG")
+	if file == "" {
+		return p, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy config: %w", err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("failed to parse policy config %s: %w", file, err)
+	}
+	for _, patterns := range [][]string{
+		p.Gmail.AllowLabels, p.Gmail.DenyLabels, p.Gmail.AllowSenders, p.Gmail.DenySenders,
+		p.Docs.AllowFolders, p.Docs.DenyFolders, p.Docs.AllowOwners, p.Docs.DenyOwners,
+		p.Calendar.AllowCalendars, p.Calendar.DenyCalendars,
+	} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid policy pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	return p, nil
+}
+
+// Enabled reports whether any Gmail rules are configured.
+func (p GmailPolicy) Enabled() bool {
+	return len(p.AllowLabels)+len(p.DenyLabels)+len(p.AllowSenders)+len(p.DenySenders) > 0
+}
+
+// HasLabelRules reports whether any label rules are configured.
+func (p GmailPolicy) HasLabelRules() bool {
+	return len(p.AllowLabels)+len(p.DenyLabels) > 0
+}
+
+// Check returns ErrPolicyDenied if a message with the given labels (names and/or
+// IDs) and sender address may not be returned.
+func (p GmailPolicy) Check(labels []string, sender string) error {
+	if err := checkRules(labels, p.AllowLabels, p.DenyLabels); err != nil {
+		return err
+	}
+	return checkRules([]string{sender}, p.AllowSenders, p.DenySenders)
+}
+
+// Enabled reports whether any Docs rules are configured.
+func (p DocsPolicy) Enabled() bool {
+	return len(p.AllowFolders)+len(p.DenyFolders)+len(p.AllowOwners)+len(p.DenyOwners) > 0
+}
+
+// HasFolderRules reports whether any folder rules are configured.
+func (p DocsPolicy) HasFolderRules() bool {
+	return len(p.AllowFolders)+len(p.DenyFolders) > 0
+}
+
+// Check returns ErrPolicyDenied if a document with the given ancestor folder IDs
+// and owner emails may not be returned.
+func (p DocsPolicy) Check(folders, owners []string) error {
+	if err := checkRules(folders, p.AllowFolders, p.DenyFolders); err != nil {
+		return err
+	}
+	return checkRules(owners, p.AllowOwners, p.DenyOwners)
+}
+
+// Enabled reports whether any Calendar rules are configured.
+func (p CalendarPolicy) Enabled() bool {
+	return len(p.AllowCalendars)+len(p.DenyCalendars) > 0
+}
+
+// Check returns ErrPolicyDenied if the calendar may not be read.
+func (p CalendarPolicy) Check(calendarID string) error {
+	return checkRules([]string{calendarID}, p.AllowCalendars, p.DenyCalendars)
+}
+
+// checkRules applies allow/deny patterns to an item's values. Deny wins; a
+// non-empty allow list requires at least one value to match. The error doesn't
+// say which rule or value matched, so it can't be used to probe the policy.
+func checkRules(values, allow, deny []string) error {
+	for _, v := range values {
+		if matchAny(deny, v) {
+			return ErrPolicyDenied
+		}
+	}
+	if len(allow) == 0 {
+		return nil
+	}
+	for _, v := range values {
+		if matchAny(allow, v) {
+			return nil
+		}
+	}
+	return ErrPolicyDenied
+}
+
+// matchAny reports whether any pattern matches v.
+func matchAny(patterns []string, v string) bool {
+	if v == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		if globMatch(pattern, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// globMatch reports whether v matches a case-insensitive glob pattern. Patterns use
+// path.Match syntax, except that "*" and "?" also match "/", so "HR/*" covers nested
+// labels such as "HR/Confidential/2024".
+func globMatch(pattern, v string) bool {
+	// path.Match wildcards never cross "/", so swap it for a character that can't
+	// appear in labels, addresses, or IDs
+	pattern = strings.ReplaceAll(strings.ToLower(pattern), "/", "\x00")
+	v = strings.ReplaceAll(strings.ToLower(v), "/", "\x00")
+	ok, _ := path.Match(pattern, v)
+	return ok
+}
@@ -0,0 +1,55 @@
+package types
+
+import "testing"
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern, value string
+		want           bool
+	}{
+		{"HR/*", "HR/Confidential", true},
+		{"HR/*", "HR/Confidential/2024", true},
+		{"hr/*", "HR/Payroll", true},
+		{"HR/*", "HR", false},
+		{"HR/*", "Finance/HR/Reviews", false},
+		{"*/Reviews", "Finance/HR/Reviews", true},
+		{"*@hr.example.com", "Alice@HR.example.com", true},
+		{"*@hr.example.com", "alice@example.com", false},
+		{"Label_?", "Label_1", true},
+		{"Label_[0-9]", "Label_x", false},
+	}
+	for _, tt := range tests {
+		if got := globMatch(tt.pattern, tt.value); got != tt.want {
+			t.Errorf("globMatch(%q, %q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestGmailPolicyCheck(t *testing.T) {
+	policy := GmailPolicy{
+		DenyLabels:   []string{"HR/*"},
+		AllowSenders: []string{"*@example.com"},
+	}
+	tests := []struct {
+		name   string
+		labels []string
+		sender string
+		denied bool
+	}{
+		{"allowed", []string{"INBOX"}, "bob@example.com", false},
+		{"nested denied label", []string{"INBOX", "HR/Confidential/2024"}, "bob@example.com", true},
+		{"sender not allowed", []string{"INBOX"}, "eve@other.com", true},
+		{"no sender", []string{"INBOX"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.labels, tt.sender)
+			if tt.denied && err != ErrPolicyDenied {
+				t.Errorf("Check = %v, want ErrPolicyDenied", err)
+			}
+			if !tt.denied && err != nil {
+				t.Errorf("Check = %v, want nil", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// callIDMetaKey is the _meta key used to carry the JSON-RPC request ID from the
+// before-call hook to the tool middleware. It is removed before handlers run.
+const callIDMetaKey = "google-workspace-mcp/call-id"
+
+// Progress sends notifications/progress for a single tool call.
+// If the client did not supply a progress token, reporting is a no-op.
+type Progress struct {
+	ctx   context.Context
+	srv   *server.MCPServer
+	token mcp.ProgressToken
+}
+
+// NewProgress creates a Progress reporter for the given tool call.
+func NewProgress(ctx context.Context, request mcp.CallToolRequest) *Progress {
+	p := &Progress{ctx: ctx, srv: server.ServerFromContext(ctx)}
+	if request.Params.Meta != nil {
+		p.token = request.Params.Meta.ProgressToken
+	}
+	return p
+}
+
+// Report sends a progress notification. A total of 0 means the total is unknown.
+// Delivery errors are ignored since progress is best-effort.
+func (p *Progress) Report(progress, total float64, message string) {
+	if p == nil || p.srv == nil || p.token == nil {
+		return
+	}
+	params := map[string]any{
+		"progressToken": p.token,
+		"progress":      progress,
+	}
+	if total > 0 {
+		params["total"] = total
+	}
+	if message != "" {
+		params["message"] = message
+	}
+	_ = p.srv.SendNotificationToClient(p.ctx, "notifications/progress", params)
+}
+
+// Cancellations tracks in-flight tool calls so that notifications/cancelled
+// from the client aborts the matching handler's context.
+type Cancellations struct {
+	mu    sync.Mutex
+	calls map[string]context.CancelFunc
+}
+
+// NewCancellations creates an empty cancellation registry.
+func NewCancellations() *Cancellations {
+	return &Cancellations{calls: map[string]context.CancelFunc{}}
+}
+
+// Hooks returns server hooks that tag each tool call with its request ID.
+func (c *Cancellations) Hooks() *server.Hooks {
+	hooks := &server.Hooks{}
+	hooks.AddBeforeCallTool(func(ctx context.Context, id any, request *mcp.CallToolRequest) {
+		if request.Params.Meta == nil {
+			request.Params.Meta = &mcp.Meta{}
+		}
+		if request.Params.Meta.AdditionalFields == nil {
+			request.Params.Meta.AdditionalFields = map[string]any{}
+		}
+		request.Params.Meta.AdditionalFields[callIDMetaKey] = fmt.Sprint(id)
+	})
+	return hooks
+}
+
+// Middleware returns a tool middleware that runs each handler with a context
+// that is cancelled when the client cancels the request.
+func (c *Cancellations) Middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if request.Params.Meta == nil {
+				return next(ctx, request)
+			}
+			id, ok := request.Params.Meta.AdditionalFields[callIDMetaKey].(string)
+			if !ok {
+				return next(ctx, request)
+			}
+			delete(request.Params.Meta.AdditionalFields, callIDMetaKey)
+
+			ctx, cancel := context.WithCancel(ctx)
+			c.mu.Lock()
+			c.calls[id] = cancel
+			c.mu.Unlock()
+			defer func() {
+				c.mu.Lock()
+				delete(c.calls, id)
+				c.mu.Unlock()
+				cancel()
+			}()
+
+			result, err := next(ctx, request)
+			if ctx.Err() != nil && err == nil {
+				return mcp.NewToolResultError("request cancelled"), nil
+			}
+			return result, err
+		}
+	}
+}
+
+// HandleCancelled handles notifications/cancelled from the client.
+func (c *Cancellations) HandleCancelled(ctx context.Context, notification mcp.JSONRPCNotification) {
+	id, ok := notification.Params.AdditionalFields["requestId"]
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	cancel, ok := c.calls[fmt.Sprint(id)]
+	c.mu.Unlock()
+	if ok {
+		cancel()
+	}
+}
@@ -0,0 +1,57 @@
+package types
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiter spaces out Google API requests to at most one per interval.
+// A zero interval disables limiting.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// GlobalRateLimiter paces multi-request operations such as fetching all pages.
+// Configure it with MCP_RATE_LIMIT (requests per second, e.g. "5" or "0.5").
+var GlobalRateLimiter = &RateLimiter{}
+
+func init() {
+	if v := os.Getenv("MCP_RATE_LIMIT"); v != "" {
+		if qps, err := strconv.ParseFloat(v, 64); err == nil && qps > 0 {
+			GlobalRateLimiter.interval = time.Duration(float64(time.Second) / qps)
+		}
+	}
+}
+
+// Wait blocks until the next request is allowed or ctx is cancelled.
+func (r *RateLimiter) Wait(ctx context.Context) error {
+	r.mu.Lock()
+	if r.interval <= 0 {
+		r.mu.Unlock()
+		return ctx.Err()
+	}
+	now := time.Now()
+	wait := r.next.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	r.next = now.Add(wait + r.interval)
+	r.mu.Unlock()
+
+	if wait == 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,233 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// RedactConfig configures PII redaction of tool output.
+// It is loaded from the JSON file named by MCP_REDACT_CONFIG.
+type RedactConfig struct {
+	// Rules lists built-in rules to enable: email, phone, card, govid, or all.
+	Rules []string `json:"rules"`
+	// Patterns are additional custom regex rules.
+	Patterns []RedactPattern `json:"patterns,omitempty"`
+	// AllowedDomains lists email domains that are not redacted (e.g. your own company).
+	// Subdomains of an allowed domain are also allowed.
+	AllowedDomains []string `json:"allowed_domains,omitempty"`
+}
+
+// RedactPattern is a custom redaction rule.
+type RedactPattern struct {
+	// Name is used in placeholders, e.g. "TICKET" produces [TICKET_1].
+	Name string `json:"name"`
+	// Regex is an RE2 regular expression matching the values to redact.
+	Regex string `json:"regex"`
+}
+
+// redactRule matches one kind of sensitive value.
+type redactRule struct {
+	label string
+	re    *regexp.Regexp
+	// valid, if set, rejects false-positive matches.
+	valid func(match string) bool
+}
+
+// builtinRedactRules maps rule names to their matchers.
+var builtinRedactRules = map[string]redactRule{
+	"email": {
+		label: "EMAIL",
+		re:    regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	},
+	"phone": {
+		label: "PHONE",
+		re:    regexp.MustCompile(`(?:\+\d{1,3}[ .\-]?)?(?:\(\d{2,4}\)|\b\d{2,4})[ .\-]\d{3,4}[ .\-]\d{3,4}\b`),
+	},
+	"card": {
+		label: "CARD",
+		re:    regexp.MustCompile(`\b\d{4}(?:[ \-]?\d{4}){2}[ \-]?\d{1,7}\b`),
+		valid: luhnValid,
+	},
+	"govid": {
+		label: "GOVID",
+		// US SSN and UK National Insurance number
+		re: regexp.MustCompile(`\b(?:\d{3}-\d{2}-\d{4}|[A-CEGHJ-PR-TW-Z]{2} ?\d{2} ?\d{2} ?\d{2} ?[A-D])\b`),
+	},
+}
+
+// builtinRedactOrder is the order built-in rules are applied. Cards run before
+// phones so long digit groups are not partially matched as phone numbers.
+var builtinRedactOrder = []string{"email", "card", "govid", "phone"}
+
+// maxRedactPlaceholders caps the distinct values a Redactor remembers placeholders for.
+const maxRedactPlaceholders = 10000
+
+// Redactor replaces sensitive values in tool output with placeholders.
+// Placeholders are stable for the lifetime of the process, so the same
+// value always maps to the same placeholder within a session. Once
+// maxRedactPlaceholders values have been seen, further new values are
+// replaced with an unnumbered placeholder such as [EMAIL].
+type Redactor struct {
+	rules          []redactRule
+	allowedDomains []string
+
+	mu           sync.Mutex
+	placeholders map[string]string // "LABEL\x00value" -> placeholder
+	counts       map[string]int    // label -> number of distinct values seen
+}
+
+// NewRedactor creates a Redactor from the given config.
+func NewRedactor(cfg RedactConfig) (*Redactor, error) {
+	r := &Redactor{
+		placeholders: map[string]string{},
+		counts:       map[string]int{},
+	}
+
+	enabled := map[string]bool{}
+	for _, name := range cfg.Rules {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "all" {
+			for n := range builtinRedactRules {
+				enabled[n] = true
+			}
+			continue
+		}
+		if _, ok := builtinRedactRules[name]; !ok {
+			return nil, fmt.Errorf("unknown redaction rule %q", name)
+		}
+		enabled[name] = true
+	}
+	for _, name := range builtinRedactOrder {
+		if enabled[name] {
+			r.rules = append(r.rules, builtinRedactRules[name])
+		}
+	}
+
+	for _, p := range cfg.Patterns {
+		if p.Name == "" {
+			return nil, fmt.Errorf("redaction pattern %q has no name", p.Regex)
+		}
+		re, err := regexp.Compile(p.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", p.Name, err)
+		}
+		r.rules = append(r.rules, redactRule{label: strings.ToUpper(p.Name), re: re})
+	}
+
+	for _, d := range cfg.AllowedDomains {
+		r.allowedDomains = append(r.allowedDomains, strings.ToLower(strings.TrimPrefix(d, "@")))
+	}
+
+	return r, nil
+}
+
+// LoadRedactor loads the redaction config named by MCP_REDACT_CONFIG.
+// It returns nil if redaction is not configured.
+func LoadRedactor() (*Redactor, error) {
+	path := os.Getenv("MCP_REDACT_CONFIG")
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read redaction config: %w", err)
+	}
+	var cfg RedactConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse redaction config %s: %w", path, err)
+	}
+	return NewRedactor(cfg)
+}
+
+// FilterText implements OutputFilter.
+func (r *Redactor) FilterText(s string) string {
+	for _, rule := range r.rules {
+		s = r.redact(s, rule)
+	}
+	return s
+}
+
+// redact replaces all matches of a single rule.
+func (r *Redactor) redact(s string, rule redactRule) string {
+	matches := rule.re.FindAllStringIndex(s, -1)
+	if len(matches) == 0 {
+		return s
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, m := range matches {
+		match := s[m[0]:m[1]]
+		if !r.shouldRedact(match, rule) {
+			continue
+		}
+		sb.WriteString(s[last:m[0]])
+		sb.WriteString(r.placeholder(rule.label, match))
+		last = m[1]
+	}
+	sb.WriteString(s[last:])
+	return sb.String()
+}
+
+// shouldRedact reports whether a match should be replaced.
+func (r *Redactor) shouldRedact(match string, rule redactRule) bool {
+	if rule.valid != nil && !rule.valid(match) {
+		return false
+	}
+	if rule.label == "EMAIL" {
+		domain := strings.ToLower(match[strings.LastIndex(match, "@")+1:])
+		for _, allowed := range r.allowedDomains {
+			if domain == allowed || strings.HasSuffix(domain, "."+allowed) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// placeholder returns the stable placeholder for a value, allocating one if needed.
+func (r *Redactor) placeholder(label, value string) string {
+	key := label + "\x00" + strings.ToLower(value)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p, ok := r.placeholders[key]; ok {
+		return p
+	}
+	if len(r.placeholders) >= maxRedactPlaceholders {
+		return "[" + label + "]"
+	}
+	r.counts[label]++
+	p := fmt.Sprintf("[%s_%d]", label, r.counts[label])
+	r.placeholders[key] = p
+	return p
+}
+
+// isDigit reports whether b is an ASCII digit.
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum.
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		if !isDigit(s[i]) {
+			continue
+		}
+		d := int(s[i] - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && n <= 19 && sum%10 == 0
+}
@@ -0,0 +1,134 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLuhnValid(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   bool
+	}{
+		{"4111111111111111", true},
+		{"4111 1111 1111 1111", true},
+		{"4111-1111-1111-1111", true},
+		{"378282246310005", true},       // 15-digit Amex test number
+		{"6011111111111117", true},      // Discover test number
+		{"4111111111111112", false},     // Bad check digit
+		{"79927398713", false},          // Valid checksum, but too short for a card
+		{"00000000000000000000", false}, // 20 digits
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := luhnValid(tt.digits); got != tt.want {
+			t.Errorf("luhnValid(%q) = %v, want %v", tt.digits, got, tt.want)
+		}
+	}
+}
+
+func TestRedactorFilterText(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  RedactConfig
+		text string
+		want string
+	}{
+		{
+			name: "card before phone",
+			cfg:  RedactConfig{Rules: []string{"all"}},
+			text: "card 4111 1111 1111 1111, phone +1 555 123 4567",
+			want: "card [CARD_1], phone [PHONE_1]",
+		},
+		{
+			name: "card with dashes",
+			cfg:  RedactConfig{Rules: []string{"card", "phone"}},
+			text: "paid with 4111-1111-1111-1111",
+			want: "paid with [CARD_1]",
+		},
+		{
+			name: "failed checksum is not a card",
+			cfg:  RedactConfig{Rules: []string{"card"}},
+			text: "order 4111 1111 1111 1112",
+			want: "order 4111 1111 1111 1112",
+		},
+		{
+			name: "phone formats",
+			cfg:  RedactConfig{Rules: []string{"phone"}},
+			text: "call (555) 123-4567 or 020 7946 0958",
+			want: "call [PHONE_1] or [PHONE_2]",
+		},
+		{
+			name: "government IDs",
+			cfg:  RedactConfig{Rules: []string{"govid"}},
+			text: "SSN 123-45-6789, NI AB 12 34 56 C",
+			want: "SSN [GOVID_1], NI [GOVID_2]",
+		},
+		{
+			name: "stable placeholders",
+			cfg:  RedactConfig{Rules: []string{"email"}},
+			text: "alice@corp.io, bob@corp.io, Alice@Corp.io",
+			want: "[EMAIL_1], [EMAIL_2], [EMAIL_1]",
+		},
+		{
+			name: "allowed domains",
+			cfg:  RedactConfig{Rules: []string{"email"}, AllowedDomains: []string{"@Example.com"}},
+			text: "a@example.com b@eng.example.com c@notexample.com d@example.com.evil.io",
+			want: "a@example.com b@eng.example.com [EMAIL_1] [EMAIL_2]",
+		},
+		{
+			name: "custom pattern",
+			cfg:  RedactConfig{Patterns: []RedactPattern{{Name: "ticket", Regex: `SEC-\d+`}}},
+			text: "see SEC-42 and SEC-7",
+			want: "see [TICKET_1] and [TICKET_2]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewRedactor(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewRedactor: %v", err)
+			}
+			if got := r.FilterText(tt.text); got != tt.want {
+				t.Errorf("FilterText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRedactorErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  RedactConfig
+	}{
+		{"unknown rule", RedactConfig{Rules: []string{"passport"}}},
+		{"unnamed pattern", RedactConfig{Patterns: []RedactPattern{{Regex: "x"}}}},
+		{"invalid pattern", RedactConfig{Patterns: []RedactPattern{{Name: "X", Regex: "("}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewRedactor(tt.cfg); err == nil {
+				t.Errorf("NewRedactor(%+v) succeeded, want error", tt.cfg)
+			}
+		})
+	}
+}
+
+func TestRedactorPlaceholderLimit(t *testing.T) {
+	r, err := NewRedactor(RedactConfig{Rules: []string{"email"}})
+	if err != nil {
+		t.Fatalf("NewRedactor: %v", err)
+	}
+	for i := range maxRedactPlaceholders {
+		r.FilterText(fmt.Sprintf("user%d@corp.io", i))
+	}
+	if got, want := r.FilterText("user0@corp.io"), "[EMAIL_1]"; got != want {
+		t.Errorf("known value = %q, want %q", got, want)
+	}
+	if got, want := r.FilterText("new@corp.io"), "[EMAIL]"; got != want {
+		t.Errorf("value beyond the limit = %q, want %q", got, want)
+	}
+	if len(r.placeholders) != maxRedactPlaceholders {
+		t.Errorf("remembered %d placeholders, want %d", len(r.placeholders), maxRedactPlaceholders)
+	}
+}